@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -13,9 +14,9 @@ import (
 
 	"github.com/go-redis/redis"
 	"github.com/gobridge/gopherbot/config"
+	"github.com/gobridge/gopherbot/config/slackclient"
 	"github.com/gobridge/gopherbot/internal/heartbeat"
 	"github.com/rs/zerolog"
-	"github.com/slack-go/slack"
 )
 
 // runServer starts the gateway HTTP server.
@@ -30,9 +31,16 @@ func runServer(cfg config.C, logger zerolog.Logger) error {
 		Str("dyno_id", cfg.Heroku.DynoID).
 		Str("commit", cfg.Heroku.Commit).
 		Str("slack_client_id", cfg.Slack.ClientID).
-		Str("log_level", cfg.LogLevel.String()).
+		Str("log_level", cfg.EffectiveLogLevel().String()).
+		Str("checksum", cfg.Checksum()).
 		Msg("configuration values")
 
+	for _, w := range cfg.Warnings() {
+		logger.Warn().
+			Str("warning", w).
+			Msg("configuration warning")
+	}
+
 	rc := redis.NewClient(config.DefaultRedis(cfg))
 	defer func() { _ = rc.Close() }()
 
@@ -63,7 +71,17 @@ func runServer(cfg config.C, logger zerolog.Logger) error {
 		return fmt.Errorf("failed to heartbeat: %w", err)
 	}
 
-	sc := slack.New(cfg.Slack.BotAccessToken, slack.OptionHTTPClient(newHTTPClient()))
+	// everything here talks to Slack, so there's nothing to run without it
+	if !cfg.SlackEnabled() {
+		return errors.New("Slack is disabled, but the bgtasks can't run without it")
+	}
+
+	cfg.HTTPClient = newHTTPClient()
+
+	sc, err := slackclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create Slack client: %w", err)
+	}
 
 	var shadowMode bool
 	if cfg.Env != config.Production {
@@ -15,6 +15,7 @@ func main() {
 	}
 
 	logger := config.DefaultLogger(cfg)
+	cfg.LogStartupBanner(logger)
 
 	if err := runServer(cfg, logger); err != nil {
 		log.Fatalf("failed to run new bgtasks server: %v", err.Error())
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -15,6 +16,7 @@ import (
 	"github.com/gobridge/gopherbot/cache"
 	"github.com/gobridge/gopherbot/cmd/consumer/playground"
 	"github.com/gobridge/gopherbot/config"
+	"github.com/gobridge/gopherbot/config/slackclient"
 	"github.com/gobridge/gopherbot/glossary"
 	"github.com/gobridge/gopherbot/handler"
 	"github.com/gobridge/gopherbot/internal/heartbeat"
@@ -64,10 +66,27 @@ func runServer(cfg config.C, logger zerolog.Logger) error {
 		Str("commit", cfg.Heroku.Commit).
 		Str("slack_request_token", cfg.Slack.RequestToken).
 		Str("slack_client_id", cfg.Slack.ClientID).
-		Str("log_level", cfg.LogLevel.String()).
+		Str("log_level", cfg.EffectiveLogLevel().String()).
+		Str("checksum", cfg.Checksum()).
 		Msg("configuration values")
 
-	sc := slack.New(cfg.Slack.BotAccessToken, slack.OptionHTTPClient(newHTTPClient()))
+	for _, w := range cfg.Warnings() {
+		logger.Warn().
+			Str("warning", w).
+			Msg("configuration warning")
+	}
+
+	// everything here talks to Slack, so there's nothing to run without it
+	if !cfg.SlackEnabled() {
+		return errors.New("Slack is disabled, but the consumer can't run without it")
+	}
+
+	cfg.HTTPClient = newHTTPClient()
+
+	sc, err := slackclient.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create Slack client: %w", err)
+	}
 
 	// test credentails and get self reference
 	self, err := getSelf(sc)
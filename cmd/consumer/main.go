@@ -15,6 +15,7 @@ func main() {
 	}
 
 	l := config.DefaultLogger(c)
+	c.LogStartupBanner(l)
 
 	if err := runServer(c, l); err != nil {
 		log.Fatalf("failed to run new consumer server: %v", err.Error())
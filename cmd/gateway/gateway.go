@@ -24,14 +24,22 @@ func runServer(cfg config.C, logger zerolog.Logger) error {
 
 	logger.Info().
 		Str("env", string(cfg.Env)).
+		Uint16("port", cfg.Port).
 		Str("app", cfg.Heroku.AppName).
 		Str("dyno_id", cfg.Heroku.DynoID).
 		Str("commit", cfg.Heroku.Commit).
 		Str("slack_request_token", cfg.Slack.RequestToken).
 		Str("slack_client_id", cfg.Slack.ClientID).
-		Str("log_level", cfg.LogLevel.String()).
+		Str("log_level", cfg.EffectiveLogLevel().String()).
+		Str("checksum", cfg.Checksum()).
 		Msg("configuration values")
 
+	for _, w := range cfg.Warnings() {
+		logger.Warn().
+			Str("warning", w).
+			Msg("configuration warning")
+	}
+
 	rc := redis.NewClient(config.DefaultRedis(cfg))
 	defer func() { _ = rc.Close() }()
 
@@ -84,21 +92,27 @@ func runServer(cfg config.C, logger zerolog.Logger) error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", hnd.handleNotFound)
 	mux.HandleFunc("/_ruok", hnd.handleRUOK)
+	mux.Handle(cfg.HealthPath, cfg.HealthHandler(config.CheckRedis(rc)))
 
 	// wrap our slack event handler in the slackSignature middleware.
 	// wrap the slackSignature middleware in the context / heroku header middleware
 	slackHandler := chMiddlewareFactory(
 		logger,
 		slackSignatureMiddlewareFactory(
-			cfg.Slack.RequestSecret, cfg.Slack.RequestToken, cfg.Slack.AppID, cfg.Slack.TeamID, &logger, hnd.handleSlackEvent,
+			cfg.Slack, &logger, hnd.handleSlackEvent,
 		),
 	)
 
-	mux.HandleFunc("/slack/event", slackHandler)
+	if cfg.SlackEnabled() {
+		mux.Handle("/slack/event", cfg.LimitBody(slackHandler))
+	} else {
+		logger.Warn().Msg("Slack is disabled, not serving Slack events")
+	}
 
 	socketAddr := fmt.Sprintf("0.0.0.0:%d", cfg.Port)
 	logger.Info().
 		Str("addr", socketAddr).
+		Bool("tls", cfg.TLS.Enabled()).
 		Msg("binding to TCP socket")
 
 	// set up the network socket
@@ -110,11 +124,7 @@ func runServer(cfg config.C, logger zerolog.Logger) error {
 	defer func() { _ = listener.Close() }()
 
 	// set up the HTTP server
-	httpSrvr := &http.Server{
-		Handler:     mux,
-		ReadTimeout: 20 * time.Second,
-		IdleTimeout: 60 * time.Second,
-	}
+	httpSrvr := config.DefaultHTTPServer(cfg, cfg.RecoveryMiddleware(logger)(mux))
 
 	serveStop, serverShutdown := make(chan struct{}), make(chan struct{})
 	var serveErr, shutdownErr error
@@ -122,7 +132,7 @@ func runServer(cfg config.C, logger zerolog.Logger) error {
 	// HTTP server parent goroutine
 	go func() {
 		defer close(serveStop)
-		serveErr = httpSrvr.Serve(listener)
+		serveErr = cfg.Serve(httpSrvr, listener)
 	}()
 
 	// signal handling / graceful shutdown goroutine
@@ -134,7 +144,7 @@ func runServer(cfg config.C, logger zerolog.Logger) error {
 			Str("signal", sig.String()).
 			Msg("shutting HTTP server down gracefully")
 
-		cctx, ccancel := context.WithTimeout(context.Background(), 25*time.Second)
+		cctx, ccancel := cfg.ShutdownContext(context.Background())
 
 		defer ccancel()
 		defer cancel()
@@ -15,6 +15,7 @@ func main() {
 	}
 
 	l := config.DefaultLogger(c)
+	c.LogStartupBanner(l)
 
 	if err := runServer(c, l); err != nil {
 		l.Fatal().
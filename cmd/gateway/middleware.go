@@ -8,7 +8,7 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/gobridge/gopherbot/signing"
+	"github.com/gobridge/gopherbot/config"
 	"github.com/rs/zerolog"
 	"github.com/valyala/fastjson"
 )
@@ -51,7 +51,9 @@ func chMiddlewareFactory(baseLogger zerolog.Logger, next http.HandlerFunc) http.
 	}
 }
 
-func slackSignatureMiddlewareFactory(hmacKey, token, appID, teamID string, baseLogger *zerolog.Logger, next http.HandlerFunc) http.HandlerFunc {
+func slackSignatureMiddlewareFactory(slack config.S, baseLogger *zerolog.Logger, next http.HandlerFunc) http.HandlerFunc {
+	token, appID, teamID := slack.RequestToken, slack.AppID, slack.TeamID
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		lc := baseLogger.With()
 
@@ -72,13 +74,9 @@ func slackSignatureMiddlewareFactory(hmacKey, token, appID, teamID string, baseL
 
 		r.Body = ioutil.NopCloser(bytes.NewReader(body))
 
-		// validate that the signature looks good
-		err = signing.Validate(hmacKey, signing.Request{
-			Body:      body,
-			Timestamp: r.Header.Get(signing.SlackTimestampHeader),
-			Signature: r.Header.Get(signing.SlackSignatureHeader),
-		})
-		if err != nil {
+		// validate that the signature looks good, and that the timestamp is
+		// within Slack.MaxTimestampSkew
+		if err := slack.VerifyRequest(r.Header, body); err != nil {
 			logger.Error().
 				Err(err).
 				Msg("failed to validated Slack request")
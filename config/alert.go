@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// alertTimeout bounds how long NotifyAlert may take, so that alerting on a
+// fatal error can't hold up the process exiting.
+const alertTimeout = 5 * time.Second
+
+// alertPayload is the JSON body NotifyAlert POSTs. Text is the field Slack
+// incoming webhooks display.
+type alertPayload struct {
+	Text   string      `json:"text"`
+	App    string      `json:"app,omitempty"`
+	Commit string      `json:"commit,omitempty"`
+	Dyno   string      `json:"dyno,omitempty"`
+	Env    Environment `json:"env"`
+}
+
+// NotifyAlert POSTs message to the AlertWebhookURL as JSON, along with the
+// Heroku app, commit, and dyno, for events ops should know about such as the
+// bot starting or hitting a fatal error. It's a no-op if AlertWebhookURL isn't
+// set, and gives up after 5 seconds.
+func (c C) NotifyAlert(ctx context.Context, message string) error {
+	if len(c.AlertWebhookURL) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(alertPayload{
+		Text:   message,
+		App:    c.Heroku.AppName,
+		Commit: c.Heroku.Commit,
+		Dyno:   c.Heroku.DynoID,
+		Env:    c.Env,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, alertTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodPost, c.AlertWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		// the URL is a secret, and err includes it
+		return errors.New("failed to build alert request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		// unwrap the *url.Error, as its message includes the URL
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err
+		}
+
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to send alert: unexpected HTTP status %d", resp.StatusCode)
+	}
+
+	return nil
+}
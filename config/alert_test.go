@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestC_NotifyAlert(t *testing.T) {
+	if err := (C{}).NotifyAlert(context.Background(), "started"); err != nil {
+		t.Fatalf("NotifyAlert() without a URL = %v, want nil", err)
+	}
+
+	var got map[string]string
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); r.Method != http.MethodPost || ct != "application/json" {
+			t.Errorf("request = %s with Content-Type %q, want a JSON POST", r.Method, ct)
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode alert: %v", err)
+		}
+
+		if strings.Contains(r.URL.Path, "fail") {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	c := C{
+		Env:        Production,
+		Heroku:     H{AppName: "gopher", Commit: "abc123", DynoID: "dyno1"},
+		HTTPClient: srv.Client(),
+	}
+
+	c.AlertWebhookURL = srv.URL + "/T123/B456"
+
+	err := c.NotifyAlert(context.Background(), "started")
+	testErrCheck(t, "NotifyAlert()", "", err)
+
+	want := map[string]string{
+		"text":   "started",
+		"app":    "gopher",
+		"commit": "abc123",
+		"dyno":   "dyno1",
+		"env":    "production",
+	}
+
+	cmpDiff(t, "alert", cmp.Diff(want, got))
+
+	c.AlertWebhookURL = srv.URL + "/fail"
+
+	err = c.NotifyAlert(context.Background(), "started")
+	testErrCheck(t, "NotifyAlert()", "failed to send alert: unexpected HTTP status 500", err)
+
+	// the URL mustn't leak in to errors
+	c.AlertWebhookURL = "https://127.0.0.1:1/T123/secret"
+
+	err = c.NotifyAlert(context.Background(), "started")
+	testErrCheck(t, "NotifyAlert()", "failed to send alert", err)
+
+	if strings.Contains(err.Error(), "secret") {
+		t.Fatalf("NotifyAlert() error includes the URL: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"io"
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// AuditSuccess is the AuditEvent result for an action that succeeded.
+	AuditSuccess = "success"
+
+	// AuditFailure is the AuditEvent result for an action that failed.
+	AuditFailure = "failure"
+
+	// AuditDenied is the AuditEvent result for an action the actor wasn't
+	// allowed to take.
+	AuditDenied = "denied"
+)
+
+// AuditEvent is an entry in the audit trail, recording who did what.
+type AuditEvent struct {
+	// Actor is who took the action, such as a Slack user ID
+	Actor string
+
+	// Action is what was done, such as the command that was run
+	Action string
+
+	// Target is what the action was taken on, such as a channel ID
+	Target string
+
+	// Result is the outcome, one of AuditSuccess, AuditFailure, or
+	// AuditDenied
+	Result string
+}
+
+// nopWriteCloser is an io.WriteCloser that doesn't close the writer, so the
+// audit log can be stdout without closing it.
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// OpenAuditLog opens cfg.AuditLogFile for appending audit events, creating it
+// if needed. If cfg.AuditLogFile is empty, it returns stdout, which closing
+// leaves open.
+func OpenAuditLog(cfg C) (io.WriteCloser, error) {
+	if len(cfg.AuditLogFile) == 0 {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+
+	return os.OpenFile(cfg.AuditLogFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600) // #nosec G304 -- the path is operator configured
+}
+
+// AuditLogger returns a logger for the audit trail, writing to w. It's
+// independent of DefaultLogger: it's never sampled, and its events are written
+// without a level so that neither the app log level nor the global level
+// filters them out. Instead, each event is labeled with cfg.AuditLogLevel.
+//
+// Use LogAudit to write events, so they all have the same fields.
+func AuditLogger(cfg C, w io.Writer) zerolog.Logger {
+	return zerolog.New(w).With().
+		Timestamp().
+		Str(zerolog.LevelFieldName, cfg.AuditLogLevel.String()).
+		Str("log", "audit").
+		Logger()
+}
+
+// LogAudit writes the audit event e to logger, which should come from
+// AuditLogger. Every field is always present, even if empty, so the audit
+// trail has a consistent schema.
+func LogAudit(logger zerolog.Logger, e AuditEvent) {
+	logger.Log().
+		Str("actor", e.Actor).
+		Str("action", e.Action).
+		Str("target", e.Target).
+		Str("result", e.Result).
+		Msg("")
+}
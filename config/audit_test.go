@@ -0,0 +1,72 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rs/zerolog"
+)
+
+func TestLogAudit(t *testing.T) {
+	// the app log level must not filter audit events
+	defer zerolog.SetGlobalLevel(zerolog.GlobalLevel())
+	zerolog.SetGlobalLevel(zerolog.ErrorLevel)
+
+	var buf bytes.Buffer
+
+	logger := AuditLogger(C{AuditLogLevel: zerolog.InfoLevel}, &buf)
+
+	LogAudit(logger, AuditEvent{Actor: "U12345", Action: "remove_user", Target: "C67890", Result: AuditSuccess})
+	LogAudit(logger, AuditEvent{Actor: "U99999", Action: "remove_user", Result: AuditDenied})
+
+	dec := json.NewDecoder(&buf)
+
+	for _, want := range []map[string]string{
+		{"level": "info", "log": "audit", "actor": "U12345", "action": "remove_user", "target": "C67890", "result": "success"},
+		{"level": "info", "log": "audit", "actor": "U99999", "action": "remove_user", "target": "", "result": "denied"},
+	} {
+		var got map[string]string
+
+		if err := dec.Decode(&got); err != nil {
+			t.Fatalf("failed to decode audit event: %v", err)
+		}
+
+		if len(got[zerolog.TimestampFieldName]) == 0 {
+			t.Error("audit event has no timestamp")
+		}
+
+		delete(got, zerolog.TimestampFieldName)
+
+		cmpDiff(t, "audit event", cmp.Diff(want, got))
+	}
+}
+
+func TestOpenAuditLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopher-audit")
+	testErrCheck(t, "ioutil.TempDir()", "", err)
+
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	p := filepath.Join(dir, "audit.log")
+
+	for i := 0; i < 2; i++ {
+		w, err := OpenAuditLog(C{AuditLogFile: p})
+		testErrCheck(t, "OpenAuditLog()", "", err)
+
+		LogAudit(AuditLogger(C{}, w), AuditEvent{Actor: "U12345", Action: "ping", Result: AuditSuccess})
+
+		testErrCheck(t, "Close()", "", w.Close())
+	}
+
+	b, err := ioutil.ReadFile(p)
+	testErrCheck(t, "ioutil.ReadFile()", "", err)
+
+	if n := bytes.Count(b, []byte("\n")); n != 2 {
+		t.Fatalf("audit log has %d events, want 2 (appended)", n)
+	}
+}
@@ -0,0 +1,295 @@
+// Package aws builds the AWS region and credentials configuration shared by
+// the loaders that talk to AWS, such as elasticache. It deliberately avoids
+// the AWS SDK, implementing just the Signature Version 4 signing and STS
+// AssumeRole call we need.
+//
+//	awsCfg, err := aws.LoadConfig(ctx, cfg)
+package aws
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gobridge/gopherbot/config"
+)
+
+// RoleSessionName is the session name used when assuming config.A.RoleARN.
+const RoleSessionName = "gopherbot"
+
+// roleDuration is how long assumed role credentials are valid for.
+const roleDuration = time.Hour
+
+// Credentials are the AWS credentials used to sign requests.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// Expires is when the credentials expire, or the zero time if they don't
+	Expires time.Time
+}
+
+// Config is the AWS configuration: the region to use and the credentials to
+// sign requests with.
+type Config struct {
+	Region      string
+	Credentials Credentials
+}
+
+// EnvCredentials loads the AWS credentials from the standard environment
+// variables.
+func EnvCredentials() (Credentials, error) {
+	c := Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+
+	if len(c.AccessKeyID) == 0 || len(c.SecretAccessKey) == 0 {
+		return Credentials{}, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+
+	return c, nil
+}
+
+// sharedCredentialsFile returns the path of the shared credentials file,
+// honoring AWS_SHARED_CREDENTIALS_FILE.
+func sharedCredentialsFile() (string, error) {
+	if p := os.Getenv("AWS_SHARED_CREDENTIALS_FILE"); len(p) > 0 {
+		return p, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".aws", "credentials"), nil
+}
+
+// ProfileCredentials loads the credentials for profile from the shared
+// credentials file (~/.aws/credentials, or AWS_SHARED_CREDENTIALS_FILE).
+func ProfileCredentials(profile string) (Credentials, error) {
+	path, err := sharedCredentialsFile()
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to find shared credentials file: %w", err)
+	}
+
+	f, err := os.Open(path) // #nosec G304 -- the path is operator configured
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to open shared credentials file: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	return parseProfileCredentials(f, profile)
+}
+
+// parseProfileCredentials reads the credentials for profile from the INI
+// formatted shared credentials file r.
+func parseProfileCredentials(r io.Reader, profile string) (Credentials, error) {
+	var (
+		c       Credentials
+		section string
+		found   bool
+	)
+
+	s := bufio.NewScanner(r)
+
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+
+		if len(line) == 0 || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			found = found || section == profile
+			continue
+		}
+
+		if section != profile {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		v := strings.TrimSpace(kv[1])
+
+		switch strings.TrimSpace(kv[0]) {
+		case "aws_access_key_id":
+			c.AccessKeyID = v
+		case "aws_secret_access_key":
+			c.SecretAccessKey = v
+		case "aws_session_token":
+			c.SessionToken = v
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return Credentials{}, fmt.Errorf("failed to read shared credentials file: %w", err)
+	}
+
+	if !found {
+		return Credentials{}, fmt.Errorf("profile %q not found in shared credentials file", profile)
+	}
+
+	if len(c.AccessKeyID) == 0 || len(c.SecretAccessKey) == 0 {
+		return Credentials{}, fmt.Errorf("profile %q is missing aws_access_key_id or aws_secret_access_key", profile)
+	}
+
+	return c, nil
+}
+
+// Client builds the AWS configuration, making the STS calls needed for role
+// assumption.
+type Client struct {
+	// STSEndpoint is the STS endpoint URL, defaulting to the regional
+	// endpoint (e.g., https://sts.us-east-1.amazonaws.com)
+	STSEndpoint string
+
+	// HTTPClient is the HTTP client used for requests, defaulting to one
+	// with a 10 second timeout
+	HTTPClient *http.Client
+}
+
+// LoadConfig builds the AWS configuration from cfg.AWS, using a zero Client.
+func LoadConfig(ctx context.Context, cfg config.C) (Config, error) {
+	var c Client
+	return c.LoadConfig(ctx, cfg)
+}
+
+// LoadConfig builds the AWS configuration from cfg.AWS. The base credentials
+// come from cfg.AWS.Profile in the shared credentials file if it's set, and
+// the AWS_* environment variables otherwise. If cfg.AWS.RoleARN is set, that
+// role is then assumed with STS and its temporary credentials are used.
+func (c *Client) LoadConfig(ctx context.Context, cfg config.C) (Config, error) {
+	if len(cfg.AWS.Region) == 0 {
+		return Config{}, errors.New("AWS.Region is not set")
+	}
+
+	var (
+		creds Credentials
+		err   error
+	)
+
+	if len(cfg.AWS.Profile) > 0 {
+		creds, err = ProfileCredentials(cfg.AWS.Profile)
+	} else {
+		creds, err = EnvCredentials()
+	}
+
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to load AWS credentials: %w", err)
+	}
+
+	if len(cfg.AWS.RoleARN) > 0 {
+		creds, err = c.assumeRole(ctx, cfg.AWS.Region, creds, cfg.AWS.RoleARN)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to assume role %s: %w", cfg.AWS.RoleARN, err)
+		}
+	}
+
+	return Config{Region: cfg.AWS.Region, Credentials: creds}, nil
+}
+
+// assumeRoleResponse is the subset of the STS AssumeRole response we use. It
+// also matches the ErrorResponse returned on failure.
+type assumeRoleResponse struct {
+	Credentials struct {
+		AccessKeyID     string    `xml:"AccessKeyId"`
+		SecretAccessKey string    `xml:"SecretAccessKey"`
+		SessionToken    string    `xml:"SessionToken"`
+		Expiration      time.Time `xml:"Expiration"`
+	} `xml:"AssumeRoleResult>Credentials"`
+
+	Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"Error"`
+}
+
+// assumeRole calls STS AssumeRole for roleARN, authenticating with creds.
+func (c *Client) assumeRole(ctx context.Context, region string, creds Credentials, roleARN string) (Credentials, error) {
+	endpoint, hc := c.STSEndpoint, c.HTTPClient
+	if len(endpoint) == 0 {
+		endpoint = "https://sts." + region + ".amazonaws.com"
+	}
+
+	if hc == nil {
+		hc = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	eu, err := url.Parse(endpoint)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to parse STS endpoint: %w", err)
+	}
+
+	u := Presign(PresignInput{
+		Method: http.MethodGet,
+		Host:   eu.Host,
+		Path:   "/",
+		Query: url.Values{
+			"Action":          {"AssumeRole"},
+			"Version":         {"2011-06-15"},
+			"RoleArn":         {roleARN},
+			"RoleSessionName": {RoleSessionName},
+			"DurationSeconds": {fmt.Sprintf("%d", int64(roleDuration/time.Second))},
+		},
+		Service:     "sts",
+		Region:      region,
+		Expires:     time.Minute,
+		PayloadHash: EmptyPayloadHash,
+		Time:        config.Now(),
+	}, creds)
+
+	// Presign always produces an http URL
+	u = eu.Scheme + strings.TrimPrefix(u, "http")
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	var ar assumeRoleResponse
+
+	if err := xml.NewDecoder(resp.Body).Decode(&ar); err != nil {
+		return Credentials{}, fmt.Errorf("failed to decode STS response (HTTP %d): %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Credentials{}, fmt.Errorf("STS returned HTTP %d: %s: %s", resp.StatusCode, ar.Error.Code, ar.Error.Message)
+	}
+
+	return Credentials{
+		AccessKeyID:     ar.Credentials.AccessKeyID,
+		SecretAccessKey: ar.Credentials.SecretAccessKey,
+		SessionToken:    ar.Credentials.SessionToken,
+		Expires:         ar.Credentials.Expiration,
+	}, nil
+}
@@ -0,0 +1,174 @@
+package aws
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobridge/gopherbot/config"
+)
+
+func setEnv(t *testing.T, env map[string]string) func() {
+	t.Helper()
+
+	for k, v := range env {
+		_ = os.Setenv(k, v)
+	}
+
+	return func() {
+		for k := range env {
+			_ = os.Unsetenv(k)
+		}
+	}
+}
+
+func Test_parseProfileCredentials(t *testing.T) {
+	const file = `[default]
+aws_access_key_id = AKIADEFAULT
+aws_secret_access_key = defaultsecret
+
+# the bot's profile
+[gopher]
+aws_access_key_id=AKIAGOPHER
+aws_secret_access_key=gophersecret
+aws_session_token=gophertoken
+
+[incomplete]
+aws_access_key_id = AKIAINCOMPLETE
+`
+
+	tests := []struct {
+		profile string
+		want    Credentials
+		err     string
+	}{
+		{profile: "default", want: Credentials{AccessKeyID: "AKIADEFAULT", SecretAccessKey: "defaultsecret"}},
+		{profile: "gopher", want: Credentials{AccessKeyID: "AKIAGOPHER", SecretAccessKey: "gophersecret", SessionToken: "gophertoken"}},
+		{profile: "incomplete", err: `profile "incomplete" is missing aws_access_key_id or aws_secret_access_key`},
+		{profile: "missing", err: `profile "missing" not found in shared credentials file`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			got, err := parseProfileCredentials(strings.NewReader(file), tt.profile)
+			if len(tt.err) > 0 {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("parseProfileCredentials() error = %v, want %s", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseProfileCredentials() unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("parseProfileCredentials() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_profile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopher-aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	p := filepath.Join(dir, "credentials")
+	if err := ioutil.WriteFile(p, []byte("[gopher]\naws_access_key_id=AKIAGOPHER\naws_secret_access_key=s\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	defer setEnv(t, map[string]string{"AWS_SHARED_CREDENTIALS_FILE": p})()
+
+	got, err := LoadConfig(context.Background(), config.C{AWS: config.A{Region: "us-west-2", Profile: "gopher"}})
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error: %v", err)
+	}
+
+	want := Config{Region: "us-west-2", Credentials: Credentials{AccessKeyID: "AKIAGOPHER", SecretAccessKey: "s"}}
+	if got != want {
+		t.Fatalf("LoadConfig() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadConfig_noRegion(t *testing.T) {
+	_, err := LoadConfig(context.Background(), config.C{})
+	if err == nil || err.Error() != "AWS.Region is not set" {
+		t.Fatalf("LoadConfig() error = %v, want AWS.Region is not set", err)
+	}
+}
+
+func TestClient_LoadConfig_assumeRole(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		"AWS_ACCESS_KEY_ID":     "AKIABASE",
+		"AWS_SECRET_ACCESS_KEY": "basesecret",
+	})()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		if q.Get("Action") != "AssumeRole" || q.Get("RoleSessionName") != RoleSessionName {
+			t.Errorf("unexpected STS query: %s", r.URL.RawQuery)
+		}
+
+		if !strings.HasPrefix(q.Get("X-Amz-Credential"), "AKIABASE/") || len(q.Get("X-Amz-Signature")) != 64 {
+			t.Errorf("STS request not presigned with the base credentials: %s", r.URL.RawQuery)
+		}
+
+		if q.Get("RoleArn") == "arn:aws:iam::123456789012:role/denied" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`<ErrorResponse><Error><Code>AccessDenied</Code><Message>nope</Message></Error></ErrorResponse>`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`<AssumeRoleResponse><AssumeRoleResult><Credentials>
+<AccessKeyId>ASIAROLE</AccessKeyId>
+<SecretAccessKey>rolesecret</SecretAccessKey>
+<SessionToken>roletoken</SessionToken>
+<Expiration>2020-05-01T13:00:00Z</Expiration>
+</Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+	}))
+
+	defer srv.Close()
+
+	c := &Client{STSEndpoint: srv.URL, HTTPClient: srv.Client()}
+	cfg := config.C{AWS: config.A{Region: "us-east-1", RoleARN: "arn:aws:iam::123456789012:role/gopherbot"}}
+
+	got, err := c.LoadConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error: %v", err)
+	}
+
+	want := Config{
+		Region: "us-east-1",
+		Credentials: Credentials{
+			AccessKeyID:     "ASIAROLE",
+			SecretAccessKey: "rolesecret",
+			SessionToken:    "roletoken",
+			Expires:         time.Date(2020, time.May, 1, 13, 0, 0, 0, time.UTC),
+		},
+	}
+
+	if got != want {
+		t.Fatalf("LoadConfig() = %+v, want %+v", got, want)
+	}
+
+	cfg.AWS.RoleARN = "arn:aws:iam::123456789012:role/denied"
+
+	_, err = c.LoadConfig(context.Background(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "STS returned HTTP 403: AccessDenied: nope") {
+		t.Fatalf("LoadConfig() error = %v, want STS AccessDenied", err)
+	}
+}
@@ -0,0 +1,132 @@
+package aws
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EmptyPayloadHash is the hex-encoded SHA-256 of an empty payload.
+const EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// PresignInput are the pieces of a request to presign with AWS Signature
+// Version 4.
+type PresignInput struct {
+	Method      string
+	Host        string
+	Path        string
+	Query       url.Values
+	Service     string
+	Region      string
+	Expires     time.Duration
+	PayloadHash string
+	Time        time.Time
+}
+
+// Presign returns the URL for the request in, presigned using AWS Signature
+// Version 4 query string authentication.
+func Presign(in PresignInput, creds Credentials) string {
+	t := in.Time.UTC()
+	date := t.Format("20060102")
+	amzDate := t.Format("20060102T150405Z")
+	scope := strings.Join([]string{date, in.Region, in.Service, "aws4_request"}, "/")
+
+	q := url.Values{}
+	for k, v := range in.Query {
+		q[k] = v
+	}
+
+	q.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	q.Set("X-Amz-Credential", creds.AccessKeyID+"/"+scope)
+	q.Set("X-Amz-Date", amzDate)
+	q.Set("X-Amz-Expires", fmt.Sprintf("%d", int64(in.Expires/time.Second)))
+	q.Set("X-Amz-SignedHeaders", "host")
+
+	if len(creds.SessionToken) > 0 {
+		q.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	query := canonicalQuery(q)
+
+	canonicalRequest := strings.Join([]string{
+		in.Method,
+		in.Path,
+		query,
+		"host:" + in.Host + "\n",
+		"host",
+		in.PayloadHash,
+	}, "\n")
+
+	crHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(crHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + creds.SecretAccessKey)
+	for _, v := range []string{date, in.Region, in.Service, "aws4_request"} {
+		key = hmacSHA256(key, v)
+	}
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return "http://" + in.Host + in.Path + "?" + query + "&X-Amz-Signature=" + signature
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	_, _ = m.Write([]byte(data))
+
+	return m.Sum(nil)
+}
+
+// canonicalQuery encodes the query string as required by AWS Signature
+// Version 4: sorted by key, with everything but the RFC 3986 unreserved
+// characters percent-encoded.
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var pairs []string
+
+	for _, k := range keys {
+		vs := append([]string(nil), q[k]...)
+		sort.Strings(vs)
+
+		for _, v := range vs {
+			pairs = append(pairs, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+func uriEncode(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerResetTimeout     = 30 * time.Second
+	defaultBreakerHalfOpenMax      = 1
+)
+
+// BreakerState is the state of a Breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed is when calls are allowed through, as normal.
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen is when calls are rejected, because too many have
+	// failed recently.
+	BreakerOpen
+
+	// BreakerHalfOpen is when a limited number of trial calls are allowed
+	// through, to see whether the remote end has recovered.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Breaker is a circuit breaker. Callers ask it whether to make a call with
+// Allow, and report the outcome of each allowed call with Success or Failure.
+// It's safe for concurrent use.
+//
+// After FailureThreshold consecutive failures the breaker opens, rejecting
+// calls for ResetTimeout. It then becomes half-open, allowing up to
+// HalfOpenMax trial calls at once: a success closes the breaker again, while a
+// failure re-opens it.
+type Breaker struct {
+	failureThreshold int
+	resetTimeout     time.Duration
+	halfOpenMax      int
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+// SlackBreaker returns a new Breaker for calls to the Slack API, configured by
+// c.Breaker.
+func (c C) SlackBreaker() *Breaker {
+	b := &Breaker{
+		failureThreshold: c.Breaker.FailureThreshold,
+		resetTimeout:     c.Breaker.ResetTimeout,
+		halfOpenMax:      c.Breaker.HalfOpenMax,
+	}
+
+	if b.failureThreshold <= 0 {
+		b.failureThreshold = defaultBreakerFailureThreshold
+	}
+
+	if b.resetTimeout <= 0 {
+		b.resetTimeout = defaultBreakerResetTimeout
+	}
+
+	if b.halfOpenMax <= 0 {
+		b.halfOpenMax = defaultBreakerHalfOpenMax
+	}
+
+	return b
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.maybeHalfOpen()
+
+	return b.state
+}
+
+// maybeHalfOpen moves an open breaker to half-open once ResetTimeout has
+// passed. b.mu must be held.
+func (b *Breaker) maybeHalfOpen() {
+	if b.state == BreakerOpen && clock.Now().Sub(b.openedAt) >= b.resetTimeout {
+		b.state = BreakerHalfOpen
+		b.trials = 0
+	}
+}
+
+// Allow returns whether a call should be made. If it returns true, the caller
+// must report the outcome with Success or Failure.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.maybeHalfOpen()
+
+	switch b.state {
+	case BreakerOpen:
+		return false
+
+	case BreakerHalfOpen:
+		if b.trials >= b.halfOpenMax {
+			return false
+		}
+
+		b.trials++
+	}
+
+	return true
+}
+
+// Success reports that an allowed call succeeded.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.trials = 0
+}
+
+// Failure reports that an allowed call failed, such as Slack responding with
+// a 5xx or rate limiting us.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+
+	if b.state == BreakerHalfOpen || b.failures >= b.failureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = clock.Now()
+		b.trials = 0
+	}
+}
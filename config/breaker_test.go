@@ -0,0 +1,73 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	clk := NewFakeClock(time.Unix(1586000000, 0))
+	defer SetClockForTest(clk)()
+
+	b := C{Breaker: B{FailureThreshold: 2, ResetTimeout: time.Minute, HalfOpenMax: 1}}.SlackBreaker()
+
+	state := func(want BreakerState) {
+		t.Helper()
+
+		if got := b.State(); got != want {
+			t.Fatalf("State() = %s, want %s", got, want)
+		}
+	}
+
+	state(BreakerClosed)
+
+	// a success resets the consecutive failure count
+	b.Failure()
+	b.Success()
+	b.Failure()
+	state(BreakerClosed)
+
+	b.Failure()
+	state(BreakerOpen)
+
+	if b.Allow() {
+		t.Fatal("Allow() = true while open")
+	}
+
+	clk.Advance(time.Minute)
+	state(BreakerHalfOpen)
+
+	if !b.Allow() {
+		t.Fatal("Allow() = false for the first half-open trial")
+	}
+
+	if b.Allow() {
+		t.Fatal("Allow() = true beyond HalfOpenMax trials")
+	}
+
+	// a failed trial re-opens the breaker
+	b.Failure()
+	state(BreakerOpen)
+
+	clk.Advance(time.Minute)
+
+	if !b.Allow() {
+		t.Fatal("Allow() = false after ResetTimeout")
+	}
+
+	// a successful trial closes it
+	b.Success()
+	state(BreakerClosed)
+
+	if !b.Allow() || !b.Allow() {
+		t.Fatal("Allow() = false while closed")
+	}
+}
+
+func TestC_SlackBreaker_defaults(t *testing.T) {
+	b := C{}.SlackBreaker()
+
+	if b.failureThreshold != 5 || b.resetTimeout != 30*time.Second || b.halfOpenMax != 1 {
+		t.Fatalf("SlackBreaker() = %d/%s/%d, want defaults of 5/30s/1", b.failureThreshold, b.resetTimeout, b.halfOpenMax)
+	}
+}
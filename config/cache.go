@@ -0,0 +1,38 @@
+package config
+
+import "time"
+
+const (
+	// CacheUser is the CacheTTL kind for Slack user lookups.
+	CacheUser = "user"
+
+	// CacheChannel is the CacheTTL kind for Slack channel lookups.
+	CacheChannel = "channel"
+)
+
+// defaultCacheTTL is the Cache.DefaultTTL if one isn't set.
+const defaultCacheTTL = time.Hour
+
+// CacheTTL returns how long lookups of kind (e.g., CacheUser) should be
+// cached for. Kinds without their own TTL, or whose TTL isn't set, use
+// Cache.DefaultTTL.
+func (c C) CacheTTL(kind string) time.Duration {
+	var ttl time.Duration
+
+	switch kind {
+	case CacheUser:
+		ttl = c.Cache.UserTTL
+	case CacheChannel:
+		ttl = c.Cache.ChannelTTL
+	}
+
+	if ttl > 0 {
+		return ttl
+	}
+
+	if c.Cache.DefaultTTL > 0 {
+		return c.Cache.DefaultTTL
+	}
+
+	return defaultCacheTTL
+}
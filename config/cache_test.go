@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestC_CacheTTL(t *testing.T) {
+	tests := []struct {
+		name  string
+		cache Cache
+		kind  string
+		want  time.Duration
+	}{
+		{name: "unset", kind: CacheUser, want: time.Hour},
+		{name: "user", cache: Cache{UserTTL: time.Minute, DefaultTTL: 2 * time.Hour}, kind: CacheUser, want: time.Minute},
+		{name: "channel", cache: Cache{ChannelTTL: 10 * time.Minute}, kind: CacheChannel, want: 10 * time.Minute},
+		{name: "channel_fallback", cache: Cache{UserTTL: time.Minute, DefaultTTL: 2 * time.Hour}, kind: CacheChannel, want: 2 * time.Hour},
+		{name: "other_kind", cache: Cache{UserTTL: time.Minute, DefaultTTL: 2 * time.Hour}, kind: "emoji", want: 2 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (C{Cache: tt.cache}).CacheTTL(tt.kind); got != tt.want {
+				t.Fatalf("CacheTTL(%q) = %s, want %s", tt.kind, got, tt.want)
+			}
+		})
+	}
+}
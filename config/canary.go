@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// canaryKey returns the name of the canary override for the environment
+// variable key. The GOPHER_ prefix is replaced, so GOPHER_SLACK_MAX_SKEW is
+// overridden by GOPHER_CANARY_SLACK_MAX_SKEW and PORT by GOPHER_CANARY_PORT.
+func canaryKey(key string) string {
+	return "GOPHER_CANARY_" + strings.TrimPrefix(key, "GOPHER_")
+}
+
+// LoadEnvCanary is LoadEnv, but on canary dynos (where GOPHER_CANARY is set)
+// each environment variable can be overridden by its GOPHER_CANARY_*
+// counterpart (see canaryKey). This lets canaries try out configuration
+// changes without a separate app config. Elsewhere, the overrides are ignored
+// and it behaves exactly like LoadEnv.
+func LoadEnvCanary() (C, error) {
+	canary, err := envBool(os.Getenv, "GOPHER_CANARY")
+	if err != nil {
+		return C{}, err
+	}
+
+	if !canary {
+		return LoadEnv()
+	}
+
+	c, err := load(func(key string) string {
+		if v, ok := os.LookupEnv(canaryKey(key)); ok {
+			return v
+		}
+
+		return os.Getenv(key)
+	})
+	if err != nil {
+		return C{}, err
+	}
+
+	for _, k := range secretEnvKeys {
+		_ = os.Unsetenv(k)            // paranoia
+		_ = os.Unsetenv(canaryKey(k)) // the overrides are secrets too
+	}
+
+	if err := c.Validate(); err != nil {
+		return C{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return c, nil
+}
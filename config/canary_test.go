@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLoadEnvCanary(t *testing.T) {
+	env := map[string]string{
+		"ENV":                           "staging",
+		"GOPHER_HEALTH_PATH":            "/healthz",
+		"GOPHER_CANARY_HEALTH_PATH":     "/canary/healthz",
+		"GOPHER_DEBUG_TOKEN":            "debug123",
+		"GOPHER_CANARY_DEBUG_TOKEN":     "canary123",
+		"GOPHER_CANARY_REDIS_FAIL_MODE": "open",
+	}
+
+	set := func() {
+		for k, v := range env {
+			_ = os.Setenv(k, v)
+		}
+	}
+
+	defer func() {
+		for k := range env {
+			_ = os.Unsetenv(k)
+		}
+
+		_ = os.Unsetenv("GOPHER_CANARY")
+	}()
+
+	t.Run("not_canary", func(t *testing.T) {
+		set()
+
+		c, err := LoadEnvCanary()
+		testErrCheck(t, "LoadEnvCanary()", "", err)
+
+		if c.Canary || c.HealthPath != "/healthz" || c.DebugToken != "debug123" || c.RedisFailMode != RedisFailClosed {
+			t.Fatalf("canary overrides applied to a non-canary dyno: %+v", c)
+		}
+	})
+
+	t.Run("canary", func(t *testing.T) {
+		set()
+		_ = os.Setenv("GOPHER_CANARY", "1")
+
+		c, err := LoadEnvCanary()
+		testErrCheck(t, "LoadEnvCanary()", "", err)
+
+		if !c.Canary {
+			t.Fatal("Canary = false, want true")
+		}
+
+		if c.HealthPath != "/canary/healthz" || c.DebugToken != "canary123" || c.RedisFailMode != RedisFailOpen {
+			t.Fatalf("canary overrides not applied: HealthPath = %q, RedisFailMode = %q", c.HealthPath, c.RedisFailMode)
+		}
+
+		// settings without an override come from the base config
+		if c.Env != Staging {
+			t.Fatalf("Env = %q, want %q", c.Env, Staging)
+		}
+
+		for _, k := range []string{"GOPHER_DEBUG_TOKEN", "GOPHER_CANARY_DEBUG_TOKEN"} {
+			if _, ok := os.LookupEnv(k); ok {
+				t.Errorf("%s is still set", k)
+			}
+		}
+	})
+
+	t.Run("bad_marker", func(t *testing.T) {
+		_ = os.Setenv("GOPHER_CANARY", "maybe")
+
+		_, err := LoadEnvCanary()
+		testErrCheck(t, "LoadEnvCanary()", `failed to parse GOPHER_CANARY: invalid boolean "maybe"`, err)
+	})
+}
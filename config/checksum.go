@@ -0,0 +1,72 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Checksum returns a hex-encoded SHA-256 checksum of the configuration, which
+// can be compared between instances to confirm they loaded the same values.
+// Secrets, fields that aren't plain values, and per-instance fields (tagged
+// `checksum:"-"`, like Heroku.DynoID and Port) are excluded, so the checksum
+// is safe to log and matches between dynos.
+func (c C) Checksum() string {
+	h := sha256.New()
+
+	for _, l := range checksumLines(c) {
+		_, _ = h.Write([]byte(l))
+		_, _ = h.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// checksumLines returns the canonical form of the configuration used by
+// Checksum: one "Path=value" line per field, with the values JSON encoded,
+// sorted by path so the checksum doesn't depend on the order the fields are
+// declared in.
+func checksumLines(c C) []string {
+	lines := checksumFields("", reflect.ValueOf(c))
+	sort.Strings(lines)
+
+	return lines
+}
+
+func checksumFields(prefix string, v reflect.Value) []string {
+	var lines []string
+
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		f := t.Field(i)
+		path := prefix + f.Name
+
+		if isIgnored(f) || isSecret(f) || f.Tag.Get("checksum") == "-" {
+			continue
+		}
+
+		if f.Type.Kind() == reflect.Struct {
+			lines = append(lines, checksumFields(path+".", v.Field(i))...)
+			continue
+		}
+
+		lines = append(lines, path+"="+checksumValue(v.Field(i)))
+	}
+
+	return lines
+}
+
+// checksumValue JSON encodes v, so that values like []string{"a b"} and
+// []string{"a", "b"} can't be confused.
+func checksumValue(v reflect.Value) string {
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprintf("%#v", v.Interface())
+	}
+
+	return string(b)
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestC_Checksum(t *testing.T) {
+	c := validC()
+	c.Heroku.Commit = "deadbeef"
+	c.Redis.TLSCipherSuites = []string{"TLS_AES_128_GCM_SHA256"}
+
+	sum := c.Checksum()
+
+	if len(sum) != 64 {
+		t.Fatalf("Checksum() = %q, want 64 hex characters", sum)
+	}
+
+	if got := c.clone().Checksum(); got != sum {
+		t.Fatalf("Checksum() of copy = %s, want %s", got, sum)
+	}
+
+	t.Run("excludes_secrets", func(t *testing.T) {
+		s := c.clone()
+		s.Slack.BotAccessToken = "xoxb-123"
+		s.Redis.Password = "hunter2"
+		s.DebugToken = "debug"
+
+		if got := s.Checksum(); got != sum {
+			t.Fatalf("Checksum() = %s after changing secrets, want %s", got, sum)
+		}
+
+		for _, l := range checksumLines(s) {
+			if strings.Contains(l, "xoxb-123") || strings.Contains(l, "hunter2") {
+				t.Fatalf("checksum line %q contains a secret", l)
+			}
+		}
+	})
+
+	t.Run("excludes_dyno_id", func(t *testing.T) {
+		s := c.clone()
+		s.Heroku.DynoID = "web.2"
+
+		if got := s.Checksum(); got != sum {
+			t.Fatalf("Checksum() = %s after changing Heroku.DynoID, want %s", got, sum)
+		}
+	})
+
+	t.Run("excludes_per_instance", func(t *testing.T) {
+		s := c.clone()
+		s.Port = 51234
+		s.MaxConcurrentEvents = 128
+
+		if got := s.Checksum(); got != sum {
+			t.Fatalf("Checksum() = %s after changing Port and MaxConcurrentEvents, want %s", got, sum)
+		}
+	})
+
+	t.Run("includes_values", func(t *testing.T) {
+		s := c.clone()
+		s.Slack.TeamID = "T123"
+
+		if got := s.Checksum(); got == sum {
+			t.Fatal("Checksum() unchanged after changing Slack.TeamID")
+		}
+
+		s = c.clone()
+		s.Redis.TLSCipherSuites = []string{"TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384"}
+
+		if got := s.Checksum(); got == sum {
+			t.Fatal("Checksum() unchanged after changing Redis.TLSCipherSuites")
+		}
+	})
+
+	t.Run("field_order", func(t *testing.T) {
+		lines := checksumLines(c)
+
+		if !sort.StringsAreSorted(lines) {
+			t.Fatalf("checksum lines aren't sorted: %q", lines)
+		}
+
+		var found bool
+
+		for _, l := range lines {
+			if l == `Heroku.Commit="deadbeef"` {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Fatalf("checksum lines %q missing Heroku.Commit", lines)
+		}
+	})
+}
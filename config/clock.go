@@ -0,0 +1,67 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time. The package reads the time through one, so
+// that tests can control it with SetClockForTest.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the Clock backed by time.Now.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// clock is the Clock used throughout the package. It's only replaced by
+// SetClockForTest.
+var clock Clock = realClock{}
+
+// SetClockForTest replaces the package's clock with c, such as a FakeClock,
+// returning a func to restore the previous one. It's only meant for tests, and
+// isn't safe to call while the package is in use by other goroutines.
+func SetClockForTest(c Clock) func() {
+	prev := clock
+	clock = c
+
+	return func() { clock = prev }
+}
+
+// Now returns the current time from the package's clock, so that the config
+// subpackages tell the time the same way and tests can control it with
+// SetClockForTest.
+func Now() time.Time {
+	return clock.Now()
+}
+
+// FakeClock is a Clock for tests that only moves when told to.
+type FakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+// NewFakeClock returns a FakeClock set to t.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{t: t}
+}
+
+// Now satisfies Clock.
+func (f *FakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.t
+}
+
+// Advance moves the clock forward by d.
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.t = f.t.Add(d)
+}
+
+var _ Clock = (*FakeClock)(nil)
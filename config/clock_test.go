@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetClockForTest(t *testing.T) {
+	start := time.Unix(1586000000, 0)
+	clk := NewFakeClock(start)
+
+	restore := SetClockForTest(clk)
+
+	if got := clock.Now(); !got.Equal(start) {
+		t.Fatalf("clock.Now() = %s, want %s", got, start)
+	}
+
+	clk.Advance(time.Minute)
+
+	if got := clock.Now(); !got.Equal(start.Add(time.Minute)) {
+		t.Fatalf("clock.Now() = %s after Advance, want %s", got, start.Add(time.Minute))
+	}
+
+	restore()
+
+	if _, ok := clock.(realClock); !ok {
+		t.Fatalf("clock = %T after restoring, want realClock", clock)
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import "context"
+
+type ctxKey uint8
+
+const (
+	ctxKeyConfig ctxKey = iota
+)
+
+// WithConfig returns a copy of ctx carrying c, so request handlers can get the
+// configuration with FromContext instead of it being threaded through every
+// function. The configuration in a context is shared by everything using that
+// context, so it must be treated as immutable: don't change its slices or
+// maps, and use Freeze if that needs to be enforced.
+func WithConfig(ctx context.Context, c C) context.Context {
+	return context.WithValue(ctx, ctxKeyConfig, c)
+}
+
+// FromContext returns the configuration stored in ctx by WithConfig. The bool
+// is false if there isn't one.
+func FromContext(ctx context.Context) (C, bool) {
+	c, ok := ctx.Value(ctxKeyConfig).(C)
+	return c, ok
+}
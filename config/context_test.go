@@ -0,0 +1,30 @@
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatal("FromContext() ok = true for a context without a configuration")
+	}
+
+	ctx := WithConfig(context.Background(), C{Env: Staging, Port: 1234})
+
+	c, ok := FromContext(ctx)
+	if !ok {
+		t.Fatal("FromContext() ok = false for a context with a configuration")
+	}
+
+	if c.Env != Staging || c.Port != 1234 {
+		t.Fatalf("FromContext() = %+v, want the configuration passed to WithConfig", c)
+	}
+
+	// a key of another type with the same value mustn't collide
+	ctx = context.WithValue(context.Background(), 0, C{Env: Production})
+
+	if _, ok := FromContext(ctx); ok {
+		t.Fatal("FromContext() ok = true for a context keyed by something else")
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Diff compares two configurations, returning a description of each field
+// that differs between them. Each entry is the field's path (e.g.,
+// "Redis.Addr") followed by the old and new values. The values of secret
+// fields are never included, instead they are only reported as changed.
+func Diff(a, b C) []string {
+	return diff("", reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+func diff(prefix string, a, b reflect.Value) []string {
+	var d []string
+
+	t := a.Type()
+
+	for i := 0; i < a.NumField(); i++ {
+		f := t.Field(i)
+		af, bf := a.Field(i), b.Field(i)
+		path := prefix + f.Name
+
+		if isIgnored(f) {
+			continue
+		}
+
+		if f.Type.Kind() == reflect.Struct {
+			d = append(d, diff(path+".", af, bf)...)
+			continue
+		}
+
+		if reflect.DeepEqual(af.Interface(), bf.Interface()) {
+			continue
+		}
+
+		if isSecret(f) {
+			d = append(d, path+": changed")
+			continue
+		}
+
+		d = append(d, fmt.Sprintf("%s: %s -> %s", path, diffValue(af), diffValue(bf)))
+	}
+
+	return d
+}
+
+// diffValue formats v for Diff, quoting strings so that empty values are
+// visible.
+func diffValue(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return fmt.Sprintf("%q", v.String())
+	}
+
+	return fmt.Sprintf("%v", v.Interface())
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiff(t *testing.T) {
+	base := C{
+		Env:             Staging,
+		Port:            8080,
+		MaxRequestBytes: 1 << 20,
+		Redis:           R{Addr: "redis.example.org:6380", Password: "hunter2"},
+		Slack:           S{TeamID: "T123", BotAccessToken: "xoxb-123"},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *C)
+		want   []string
+	}{
+		{
+			name:   "same",
+			modify: func(*C) {},
+		},
+		{
+			name: "top_level",
+			modify: func(c *C) {
+				c.Env = Production
+				c.Port = 9090
+			},
+			want: []string{
+				`Env: "staging" -> "production"`,
+				`Port: 8080 -> 9090`,
+			},
+		},
+		{
+			name: "nested",
+			modify: func(c *C) {
+				c.Redis.Addr = "redis.example.com:6380"
+				c.Redis.TLSCipherSuites = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}
+				c.Slack.TeamID = "T456"
+			},
+			want: []string{
+				`Redis.Addr: "redis.example.org:6380" -> "redis.example.com:6380"`,
+				`Redis.TLSCipherSuites: [] -> [TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256]`,
+				`Slack.TeamID: "T123" -> "T456"`,
+			},
+		},
+		{
+			name: "secrets_masked",
+			modify: func(c *C) {
+				c.Redis.Password = "hunter3"
+				c.Slack.BotAccessToken = ""
+				c.DebugToken = "debug123"
+			},
+			want: []string{
+				"Redis.Password: changed",
+				"Slack.BotAccessToken: changed",
+				"DebugToken: changed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := base
+			tt.modify(&b)
+
+			got := Diff(base, b)
+
+			cmpDiff(t, "Diff()", cmp.Diff(tt.want, got))
+
+			for _, line := range got {
+				for _, secret := range []string{"hunter2", "hunter3", "xoxb-123", "debug123"} {
+					if strings.Contains(line, secret) {
+						t.Fatalf("Diff() line %q leaks secret %q", line, secret)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// dirKey converts an environment variable name to the filename LoadDir expects
+// it to be stored in. The GOPHER_ prefix is dropped and the name lowercased, so
+// GOPHER_SLACK_BOT_ACCESS_TOKEN becomes slack_bot_access_token and PORT
+// becomes port.
+func dirKey(envKey string) string {
+	return strings.ToLower(strings.TrimPrefix(envKey, "GOPHER_"))
+}
+
+// LoadDir loads the configuration from a directory where each filename is a
+// configuration key (e.g., "slack_bot_access_token") and its contents are the
+// value. This is the pattern used by Kubernetes ConfigMap and Secret volume
+// mounts. Each key is the name of the environment variable LoadEnv would read,
+// lowercased and without the GOPHER_ prefix.
+//
+// Trailing newlines are trimmed from values, and dotfiles and subdirectories
+// are skipped.
+func LoadDir(dir string) (C, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return C{}, fmt.Errorf("failed to read config directory: %w", err)
+	}
+
+	values := make(map[string]string, len(files))
+
+	for _, f := range files {
+		name := f.Name()
+
+		if strings.HasPrefix(name, ".") || f.IsDir() {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return C{}, fmt.Errorf("failed to read config file %s: %w", name, err)
+		}
+
+		values[name] = strings.TrimRight(string(b), "\r\n")
+	}
+
+	return LoadMap(values)
+}
+
+// LoadMap loads the configuration from a map of configuration keys to values,
+// using the same keys as LoadDir (e.g., "slack_bot_access_token").
+func LoadMap(values map[string]string) (C, error) {
+	c, err := load(func(key string) string { return values[dirKey(key)] })
+	if err != nil {
+		return C{}, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return C{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return c, nil
+}
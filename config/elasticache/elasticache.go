@@ -0,0 +1,82 @@
+// Package elasticache provides support for authenticating to AWS ElastiCache
+// for Redis using IAM authentication, where the Redis password is a
+// short-lived token signed with AWS credentials.
+//
+// The token provider plugs in to the config.R.PasswordProvider hook:
+//
+//	cfg.Redis.User = "gopher"
+//	cfg.Redis.PasswordProvider = elasticache.TokenProvider(cfg, "gopher-cache", "gopher")
+package elasticache
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gobridge/gopherbot/config"
+	"github.com/gobridge/gopherbot/config/aws"
+)
+
+// TokenExpiry is how long a generated token is valid for. ElastiCache only
+// checks this when a connection is established, so existing connections
+// continue to work after the token expires.
+const TokenExpiry = 15 * time.Minute
+
+// credentialsRefreshMargin is how long before assumed role credentials expire
+// that TokenProvider loads new ones, so tokens aren't signed with credentials
+// that are about to expire.
+const credentialsRefreshMargin = 5 * time.Minute
+
+// TokenProvider returns a function that generates an IAM authentication token
+// for userID on the ElastiCache replication group (or serverless cache)
+// cacheName. The token is signed with the AWS configuration aws.LoadConfig
+// builds from cfg.AWS, so it's for cfg.AWS.Region and uses the credentials
+// of cfg.AWS.Profile, or the AWS_* environment variables, and assumes
+// cfg.AWS.RoleARN if that's set.
+//
+// Tokens expire after 15 minutes, so a new one is generated every time the
+// function is called, which config.DefaultRedis does for every new connection.
+// Assumed role credentials are reused until they're close to expiring, rather
+// than calling STS each time.
+func TokenProvider(cfg config.C, cacheName, userID string) func() (string, error) {
+	var (
+		mu     sync.Mutex
+		awsCfg aws.Config
+	)
+
+	return func() (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if exp := awsCfg.Credentials.Expires; exp.IsZero() || !config.Now().Before(exp.Add(-credentialsRefreshMargin)) {
+			c, err := aws.LoadConfig(context.Background(), cfg)
+			if err != nil {
+				return "", err
+			}
+
+			awsCfg = c
+		}
+
+		q := url.Values{
+			"Action": {"connect"},
+			"User":   {userID},
+		}
+
+		u := aws.Presign(aws.PresignInput{
+			Method:      "GET",
+			Host:        cacheName,
+			Path:        "/",
+			Query:       q,
+			Service:     "elasticache",
+			Region:      awsCfg.Region,
+			Expires:     TokenExpiry,
+			PayloadHash: aws.EmptyPayloadHash,
+			Time:        config.Now(),
+		}, awsCfg.Credentials)
+
+		// the token is the presigned URL without the scheme
+		return strings.TrimPrefix(u, "http://"), nil
+	}
+}
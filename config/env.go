@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// firstEnv returns the value of the first of keys that's set, using getenv to
+// look them up.
+func firstEnv(getenv func(string) string, keys ...string) string {
+	for _, k := range keys {
+		if v := getenv(k); len(v) > 0 {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// parseBool parses a boolean environment variable value. It's more forgiving
+// than strconv.ParseBool, accepting 1, true, yes, and on as true and 0, false,
+// no, and off as false, in any case. Anything else is an error, rather than
+// silently being false.
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "true", "yes", "on":
+		return true, nil
+	case "0", "false", "no", "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid boolean %q, expected 1, true, yes, or on, or 0, false, no, or off", s)
+	}
+}
+
+// envBool parses the boolean environment variable key with parseBool, using
+// getenv to look it up. It's false if the variable isn't set.
+func envBool(getenv func(string) string, key string) (bool, error) {
+	return envBoolDefault(getenv, key, false)
+}
+
+// envBoolDefault is envBool, except that it's def if the variable isn't set.
+// Toggles whose default differs by environment (e.g., on in Production and
+// off in Development) should use it with the default computed from the
+// environment, and document the default next to their Env line.
+func envBoolDefault(getenv func(string) string, key string, def bool) (bool, error) {
+	v := getenv(key)
+	if len(v) == 0 {
+		return def, nil
+	}
+
+	b, err := parseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", key, err)
+	}
+
+	return b, nil
+}
+
+// envDuration parses the duration environment variable key, using getenv to
+// look it up. It's def if the variable isn't set. Unlike time.ParseDuration
+// alone, it requires a unit even for zero, as a bare number is more likely a
+// mistake for seconds than nanoseconds, and it rejects negative durations as
+// they're all timeouts, TTLs, or intervals.
+func envDuration(getenv func(string) string, key string, def time.Duration) (time.Duration, error) {
+	v := getenv(key)
+	if len(v) == 0 {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", key, err)
+	}
+
+	// time.ParseDuration only accepts a bare number if it's zero
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return 0, fmt.Errorf("failed to parse %s: missing unit in duration %q", key, v)
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("failed to parse %s: duration must not be negative, got %s", key, v)
+	}
+
+	return d, nil
+}
+
+// splitList splits a comma-separated environment variable value in to its
+// elements, trimming whitespace around each and dropping empty ones.
+func splitList(s string) []string {
+	var l []string
+
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			l = append(l, v)
+		}
+	}
+
+	return l
+}
+
+// parseLogLevel parses a zerolog level name (e.g., "info"), or its integer
+// value from -1 (trace) to 5 (panic) as some deployment tooling sets those.
+func parseLogLevel(s string) (zerolog.Level, error) {
+	l, err := zerolog.ParseLevel(s)
+	if err == nil {
+		return l, nil
+	}
+
+	i, ierr := strconv.Atoi(s)
+	if ierr != nil {
+		return l, err
+	}
+
+	if i < int(zerolog.TraceLevel) || i > int(zerolog.PanicLevel) {
+		return zerolog.NoLevel, fmt.Errorf("log level %d out of range (%d to %d)", i, zerolog.TraceLevel, zerolog.PanicLevel)
+	}
+
+	return zerolog.Level(i), nil
+}
+
+// expandEnv replaces $VAR and ${VAR} references in s with their values from
+// lookup, using os.Expand. A default can be given with ${VAR:-default}, which
+// is used if VAR is unset or empty. References to variables that are unset
+// without a default are an error, so that typos don't silently result in
+// empty values.
+func expandEnv(s string, lookup func(string) (string, bool)) (string, error) {
+	var missing []string
+
+	v := os.Expand(s, func(name string) string {
+		def, hasDef := "", false
+
+		if i := strings.Index(name, ":-"); i >= 0 {
+			name, def, hasDef = name[:i], name[i+2:], true
+		}
+
+		val, ok := lookup(name)
+
+		switch {
+		case ok && (len(val) > 0 || !hasDef):
+			return val
+		case hasDef:
+			return def
+		default:
+			missing = append(missing, name)
+			return ""
+		}
+	})
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("undefined variables referenced: %s", strings.Join(missing, ", "))
+	}
+
+	return v, nil
+}
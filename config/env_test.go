@@ -0,0 +1,172 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func Test_parseLogLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want zerolog.Level
+		err  string
+	}{
+		{name: "name", in: "warn", want: zerolog.WarnLevel},
+		{name: "trace_name", in: "trace", want: zerolog.TraceLevel},
+		{name: "int", in: "1", want: zerolog.InfoLevel},
+		{name: "int_trace", in: "-1", want: zerolog.TraceLevel},
+		{name: "int_panic", in: "5", want: zerolog.PanicLevel},
+		{name: "int_too_low", in: "-2", err: "log level -2 out of range (-1 to 5)"},
+		{name: "int_too_high", in: "7", err: "log level 7 out of range (-1 to 5)"},
+		{name: "unknown", in: "loud", err: "Unknown Level String: 'loud'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLogLevel(tt.in)
+			testErrCheck(t, "parseLogLevel()", tt.err, err)
+
+			if len(tt.err) > 0 {
+				return
+			}
+
+			if got != tt.want {
+				t.Fatalf("parseLogLevel() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseBool(t *testing.T) {
+	for _, in := range []string{"1", "true", "True", "TRUE", "yes", "Yes", "on", "ON", " true "} {
+		got, err := parseBool(in)
+		testErrCheck(t, "parseBool("+in+")", "", err)
+
+		if !got {
+			t.Errorf("parseBool(%q) = false, want true", in)
+		}
+	}
+
+	for _, in := range []string{"0", "false", "False", "no", "NO", "off", "Off"} {
+		got, err := parseBool(in)
+		testErrCheck(t, "parseBool("+in+")", "", err)
+
+		if got {
+			t.Errorf("parseBool(%q) = true, want false", in)
+		}
+	}
+
+	for _, in := range []string{"2", "y", "enabled", ""} {
+		if _, err := parseBool(in); err == nil {
+			t.Errorf("parseBool(%q) expected an error", in)
+		}
+	}
+}
+
+func Test_envBoolDefault(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		def   bool
+		want  bool
+		err   string
+	}{
+		{name: "unset_default_true", def: true, want: true},
+		{name: "unset_default_false", def: false, want: false},
+		{name: "set_true", value: "on", def: false, want: true},
+		{name: "set_false", value: "off", def: true, want: false},
+		{name: "invalid", value: "maybe", def: true, err: `failed to parse GOPHER_TOGGLE: invalid boolean "maybe"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(string) string { return tt.value }
+
+			got, err := envBoolDefault(getenv, "GOPHER_TOGGLE", tt.def)
+			if cont := testErrCheck(t, "envBoolDefault()", tt.err, err); !cont {
+				return
+			}
+
+			if got != tt.want {
+				t.Fatalf("envBoolDefault() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_envDuration(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+		err   string
+	}{
+		{name: "unset", want: time.Minute},
+		{name: "seconds", value: "5s", want: 5 * time.Second},
+		{name: "milliseconds", value: "100ms", want: 100 * time.Millisecond},
+		{name: "zero_with_unit", value: "0s", want: 0},
+		{name: "bare_number", value: "5", err: `failed to parse GOPHER_TIMEOUT: time: missing unit in duration "5"`},
+		{name: "bare_zero", value: "0", err: `failed to parse GOPHER_TIMEOUT: missing unit in duration "0"`},
+		{name: "negative", value: "-1s", err: "failed to parse GOPHER_TIMEOUT: duration must not be negative, got -1s"},
+		{name: "invalid", value: "soon", err: `failed to parse GOPHER_TIMEOUT: time: invalid duration "soon"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(string) string { return tt.value }
+
+			got, err := envDuration(getenv, "GOPHER_TIMEOUT", time.Minute)
+			if cont := testErrCheck(t, "envDuration()", tt.err, err); !cont {
+				return
+			}
+
+			if got != tt.want {
+				t.Fatalf("envDuration() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_expandEnv(t *testing.T) {
+	vars := map[string]string{
+		"REDIS_URL": "redis://localhost:6379",
+		"EMPTY":     "",
+	}
+
+	lookup := func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+		err  string
+	}{
+		{name: "plain", in: "testing", want: "testing"},
+		{name: "braces", in: "${REDIS_URL}", want: "redis://localhost:6379"},
+		{name: "bare", in: "$REDIS_URL/0", want: "redis://localhost:6379/0"},
+		{name: "default_unused", in: "${REDIS_URL:-redis://fallback}", want: "redis://localhost:6379"},
+		{name: "default_unset", in: "${NOT_SET:-debug}", want: "debug"},
+		{name: "default_empty", in: "${EMPTY:-debug}", want: "debug"},
+		{name: "empty_no_default", in: "a${EMPTY}b", want: "ab"},
+		{name: "undefined", in: "${NOT_SET}/$ALSO_NOT_SET", err: "undefined variables referenced: ALSO_NOT_SET, NOT_SET"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandEnv(tt.in, lookup)
+			if cont := testErrCheck(t, "expandEnv()", tt.err, err); !cont {
+				return
+			}
+
+			if got != tt.want {
+				t.Fatalf("expandEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
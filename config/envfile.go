@@ -0,0 +1,90 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadEnvFile loads the configuration from a file of KEY=VALUE lines, where
+// each key is the name of the environment variable LoadEnv would read (e.g.,
+// GOPHER_SLACK_TEAM_ID). Blank lines and lines starting with # are skipped, an
+// optional "export " prefix is allowed, and values may be wrapped in single or
+// double quotes.
+//
+// Values reference environment variables with $VAR or ${VAR}, which are
+// expanded when the file is loaded, so that the file can hold the structure of
+// the configuration while the secrets come from the environment (e.g.,
+// REDIS_URL=${REDIS_URL}). Use ${VAR:-default} to fall back to a default if
+// VAR is unset or empty, as referencing an unset variable is an error. Single
+// quoted values are not expanded. Variables are looked up in the keys defined
+// earlier in the file before the environment.
+func LoadEnvFile(path string) (C, error) {
+	f, err := os.Open(path) // #nosec G304 -- path is provided by the operator
+	if err != nil {
+		return C{}, fmt.Errorf("failed to open config file: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	values := make(map[string]string)
+
+	lookup := func(key string) (string, bool) {
+		if v, ok := values[key]; ok {
+			return v, true
+		}
+
+		return os.LookupEnv(key)
+	}
+
+	s := bufio.NewScanner(f)
+
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		i := strings.Index(line, "=")
+		if i < 1 {
+			return C{}, fmt.Errorf("failed to parse config file line %d: expected KEY=VALUE", n)
+		}
+
+		key, v := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+
+		switch {
+		case len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'':
+			v = v[1 : len(v)-1]
+
+		default:
+			if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+				v = v[1 : len(v)-1]
+			}
+
+			if v, err = expandEnv(v, lookup); err != nil {
+				return C{}, fmt.Errorf("failed to expand %s on config file line %d: %w", key, n, err)
+			}
+		}
+
+		values[key] = v
+	}
+
+	if err := s.Err(); err != nil {
+		return C{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	c, err := load(func(key string) string { return values[key] })
+	if err != nil {
+		return C{}, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return C{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return c, nil
+}
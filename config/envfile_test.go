@@ -0,0 +1,99 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rs/zerolog"
+)
+
+func TestLoadEnvFile(t *testing.T) {
+	_ = os.Setenv("GOPHER_TEST_REDIS_PASSWORD", "hunter2")
+	defer func() { _ = os.Unsetenv("GOPHER_TEST_REDIS_PASSWORD") }()
+
+	tests := []struct {
+		name string
+		file string
+		err  string
+		want C
+	}{
+		{
+			name: "values",
+			file: `# gopher configuration
+ENV=testing
+export PORT=1234
+REDIS_ADDR=redis.example.org:4321
+REDIS_URL="rediss://u:${GOPHER_TEST_REDIS_PASSWORD}@${REDIS_ADDR}"
+GOPHER_LOG_LEVEL=${GOPHER_TEST_LOG_LEVEL:-debug}
+GOPHER_SLACK_TEAM_ID='$T123'
+`,
+			want: C{
+				LogLevel:                 zerolog.DebugLevel,
+				Env:                      Testing,
+				Port:                     1234,
+				MaxRequestBytes:          1 << 20,
+				HealthPath:               "/healthz",
+				Timezone:                 "UTC",
+				Locale:                   "en-US",
+				MaxConcurrentEvents:      runtime.NumCPU() * 4,
+				RedisFailMode:            RedisFailClosed,
+				MaxMessageLength:         40000,
+				MaxBlocksPerMessage:      50,
+				RedisPoolMetricsInterval: 15 * time.Second,
+				AuditLogLevel:            zerolog.InfoLevel,
+				MaintenanceMessage:       defaultMaintenanceMessage,
+				Redis: R{
+					Addr:     "redis.example.org:4321",
+					User:     "u",
+					Password: "hunter2",
+					DB:       2,
+				},
+				Slack: S{
+					MaxTimestampSkew:  5 * time.Minute,
+					AckMode:           AckModeImmediate,
+					AckTimeout:        2500 * time.Millisecond,
+					MaxRetryNum:       3,
+					CommandPrefix:     "!",
+					RespondToMentions: true,
+					TeamID:            "$T123",
+				},
+			},
+		},
+		{
+			name: "undefined_variable",
+			file: "ENV=testing\nREDIS_URL=${GOPHER_TEST_NOT_SET}\n",
+			err:  "failed to expand REDIS_URL on config file line 2: undefined variables referenced: GOPHER_TEST_NOT_SET",
+		},
+		{
+			name: "bad_line",
+			file: "ENV=testing\nPORT\n",
+			err:  "failed to parse config file line 2: expected KEY=VALUE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "gopher-config")
+			testErrCheck(t, "ioutil.TempDir()", "", err)
+
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			p := filepath.Join(dir, "gopher.env")
+
+			err = ioutil.WriteFile(p, []byte(tt.file), 0o600)
+			testErrCheck(t, "ioutil.WriteFile()", "", err)
+
+			got, err := LoadEnvFile(p)
+			if cont := testErrCheck(t, "LoadEnvFile()", tt.err, err); !cont {
+				return
+			}
+
+			cmpDiff(t, "C", cmp.Diff(tt.want, got))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"expvar"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	expvarMu      sync.Mutex
+	expvarConfigs = make(map[string]C)
+)
+
+// secretsSet records in m whether each secret field of the struct v is set,
+// keyed by the serialized field path with a _set suffix (e.g.,
+// "slack.bot_access_token_set"), matching the names LoadEnvLogged uses.
+func secretsSet(prefix string, v reflect.Value, m map[string]bool) {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+
+		switch {
+		case isIgnored(f):
+			continue
+
+		case isSecret(f):
+			m[prefix+fieldName(f)+"_set"] = !fv.IsZero()
+
+		case fv.Kind() == reflect.Struct:
+			secretsSet(prefix+fieldName(f)+".", fv, m)
+		}
+	}
+}
+
+// expvarMap is the value ExpvarSnapshot publishes.
+func (c C) expvarMap() map[string]interface{} {
+	secrets := make(map[string]bool)
+	secretsSet("", reflect.ValueOf(c), secrets)
+
+	return map[string]interface{}{
+		"env":     c.Env,
+		"commit":  c.Heroku.Commit,
+		"port":    c.Port,
+		"secrets": secrets,
+	}
+}
+
+// ExpvarSnapshot returns an expvar.Var exposing the environment, commit, port,
+// and whether each secret is set. Secret values are never included.
+func (c C) ExpvarSnapshot() expvar.Var {
+	return expvar.Func(func() interface{} { return c.expvarMap() })
+}
+
+// PublishExpvar publishes the ExpvarSnapshot of c as the expvar name, so it's
+// served from /debug/vars. It's safe to call more than once with the same
+// name (e.g., after reloading the configuration), in which case the published
+// snapshot is replaced with c. It returns an error if name is already
+// published by something else, rather than panicking like expvar.Publish.
+func (c C) PublishExpvar(name string) error {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	if _, ok := expvarConfigs[name]; !ok {
+		if expvar.Get(name) != nil {
+			return fmt.Errorf("expvar %s is already published", name)
+		}
+
+		expvar.Publish(name, expvar.Func(func() interface{} {
+			expvarMu.Lock()
+			cfg := expvarConfigs[name]
+			expvarMu.Unlock()
+
+			return cfg.expvarMap()
+		}))
+	}
+
+	expvarConfigs[name] = c
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/json"
+	"expvar"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestC_ExpvarSnapshot(t *testing.T) {
+	c := C{
+		Env:        Production,
+		Port:       1234,
+		Heroku:     H{Commit: "deadbeef"},
+		Redis:      R{Password: "hunter2"},
+		Slack:      S{BotAccessToken: "xoxb-123"},
+		DebugToken: "",
+	}
+
+	s := c.ExpvarSnapshot().String()
+
+	for _, secret := range []string{"hunter2", "xoxb-123"} {
+		if strings.Contains(s, secret) {
+			t.Fatalf("ExpvarSnapshot() = %s, contains secret %q", s, secret)
+		}
+	}
+
+	var got struct {
+		Env     string          `json:"env"`
+		Commit  string          `json:"commit"`
+		Port    uint16          `json:"port"`
+		Secrets map[string]bool `json:"secrets"`
+	}
+
+	if err := json.Unmarshal([]byte(s), &got); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", s, err)
+	}
+
+	if got.Env != "production" || got.Commit != "deadbeef" || got.Port != 1234 {
+		t.Fatalf("ExpvarSnapshot() = %s", s)
+	}
+
+	want := map[string]bool{
+		"redis.password_set":         true,
+		"slack.bot_access_token_set": true,
+		"slack.app_level_token_set":  false,
+		"slack.client_secret_set":    false,
+		"slack.request_secret_set":   false,
+		"slack.request_token_set":    false,
+		"debug_token_set":            false,
+		"alert_webhook_url_set":      false,
+	}
+
+	cmpDiff(t, "secrets", cmp.Diff(want, got.Secrets))
+}
+
+func TestC_PublishExpvar(t *testing.T) {
+	testErrCheck(t, "PublishExpvar()", "", C{Env: Staging}.PublishExpvar("gopher_test_config"))
+	testErrCheck(t, "PublishExpvar()", "", C{Env: Production}.PublishExpvar("gopher_test_config"))
+
+	if s := expvar.Get("gopher_test_config").String(); !strings.Contains(s, `"env":"production"`) {
+		t.Fatalf("expvar = %s, want the latest configuration", s)
+	}
+
+	expvar.NewInt("gopher_test_taken")
+
+	testErrCheck(t, "PublishExpvar()", "expvar gopher_test_taken is already published", C{}.PublishExpvar("gopher_test_taken"))
+}
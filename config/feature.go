@@ -0,0 +1,79 @@
+package config
+
+import "strings"
+
+// FeatureDebugCommand is the feature flag for the verbose debug command.
+const FeatureDebugCommand = "debugcmd"
+
+// builtinFeatures are the known feature flags, and their built-in default in
+// each environment. Environments not listed default to off.
+var builtinFeatures = map[string]map[Environment]bool{
+	FeatureDebugCommand: {Development: true},
+}
+
+// featureEnvSuffixes are the suffixes of the environment-scoped feature flag
+// environment variables, like GOPHER_FEATURE_DEBUGCMD_DEV.
+var featureEnvSuffixes = map[Environment]string{
+	Development: "DEV",
+	Testing:     "TEST",
+	Staging:     "STAGING",
+	Production:  "PROD",
+}
+
+// loadFeatures loads the global and env-scoped values of each of the known
+// feature flags, using getenv to look them up. The maps are nil if none
+// are set.
+func loadFeatures(getenv func(string) string, env Environment) (global, scoped map[string]bool, err error) {
+	set := func(m map[string]bool, name, key string) (map[string]bool, error) {
+		if len(getenv(key)) == 0 {
+			return m, nil
+		}
+
+		b, err := envBool(getenv, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if m == nil {
+			m = make(map[string]bool)
+		}
+
+		m[name] = b
+
+		return m, nil
+	}
+
+	for name := range builtinFeatures {
+		key := "GOPHER_FEATURE_" + strings.ToUpper(name)
+
+		if global, err = set(global, name, key); err != nil {
+			return nil, nil, err
+		}
+
+		if scoped, err = set(scoped, name, key+"_"+featureEnvSuffixes[env]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return global, scoped, nil
+}
+
+// FeatureEnabled returns whether the feature flag name (e.g.,
+// FeatureDebugCommand) is on. The first of these that's set wins:
+//
+//  1. the value for the current environment (GOPHER_FEATURE_<NAME>_<ENV>)
+//  2. the global value (GOPHER_FEATURE_<NAME>)
+//  3. the built-in default for the current environment
+//
+// Unknown features are off.
+func (c C) FeatureEnabled(name string) bool {
+	if b, ok := c.EnvFeatures[name]; ok {
+		return b
+	}
+
+	if b, ok := c.Features[name]; ok {
+		return b
+	}
+
+	return builtinFeatures[name][c.Env]
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_loadFeatures(t *testing.T) {
+	env := map[string]string{
+		"GOPHER_FEATURE_DEBUGCMD":      "off",
+		"GOPHER_FEATURE_DEBUGCMD_PROD": "on",
+	}
+
+	getenv := func(k string) string { return env[k] }
+
+	global, scoped, err := loadFeatures(getenv, Production)
+	testErrCheck(t, "loadFeatures()", "", err)
+	cmpDiff(t, "global", cmp.Diff(map[string]bool{FeatureDebugCommand: false}, global))
+	cmpDiff(t, "scoped", cmp.Diff(map[string]bool{FeatureDebugCommand: true}, scoped))
+
+	// the _PROD value only applies in Production
+	global, scoped, err = loadFeatures(getenv, Staging)
+	testErrCheck(t, "loadFeatures()", "", err)
+	cmpDiff(t, "global", cmp.Diff(map[string]bool{FeatureDebugCommand: false}, global))
+	cmpDiff(t, "scoped", cmp.Diff(map[string]bool(nil), scoped))
+
+	env["GOPHER_FEATURE_DEBUGCMD_STAGING"] = "sometimes"
+
+	_, _, err = loadFeatures(getenv, Staging)
+	testErrCheck(t, "loadFeatures()", `failed to parse GOPHER_FEATURE_DEBUGCMD_STAGING: invalid boolean "sometimes"`, err)
+}
+
+func TestC_FeatureEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		c    C
+		want bool
+	}{
+		{name: "builtin_development", c: C{Env: Development}, want: true},
+		{name: "builtin_staging", c: C{Env: Staging}, want: false},
+		{name: "builtin_production", c: C{Env: Production}, want: false},
+		{
+			name: "global_over_builtin",
+			c:    C{Env: Production, Features: map[string]bool{FeatureDebugCommand: true}},
+			want: true,
+		},
+		{
+			name: "global_off_in_development",
+			c:    C{Env: Development, Features: map[string]bool{FeatureDebugCommand: false}},
+			want: false,
+		},
+		{
+			name: "env_over_global",
+			c: C{
+				Env:         Production,
+				Features:    map[string]bool{FeatureDebugCommand: true},
+				EnvFeatures: map[string]bool{FeatureDebugCommand: false},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.FeatureEnabled(FeatureDebugCommand); got != tt.want {
+				t.Fatalf("FeatureEnabled() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+
+	if (C{Env: Development}).FeatureEnabled("unknown") {
+		t.Fatal("FeatureEnabled(unknown) = true, want false")
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LoadFiles loads the configuration from layered config files, such as a
+// base.yaml, then production.yaml, then local overrides. Files are applied in
+// order, field by field, so a later file only overrides the fields it sets.
+// The first file must exist, but any later missing ones are skipped.
+//
+// Files ending in .yaml or .yml are parsed as YAML, and those ending in .json
+// or .jsonc as JSON with comments (see LoadJSONC). Keys are the json tags on
+// C, and values are parsed as by Set: so durations are strings like "30s" and
+// log levels are names like "info", matching MarshalYAMLRedacted's output.
+func LoadFiles(paths ...string) (C, error) {
+	if len(paths) == 0 {
+		return C{}, fmt.Errorf("no config files given")
+	}
+
+	c, err := load(func(string) string { return "" })
+	if err != nil {
+		return C{}, err
+	}
+
+	set := make(map[string]bool)
+
+	for i, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			if i > 0 && os.IsNotExist(err) {
+				continue
+			}
+
+			return C{}, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+
+		values, err := decodeConfigFile(path, b)
+		if err != nil {
+			return C{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if err := c.Set(k, values[k]); err != nil {
+				return C{}, fmt.Errorf("failed to load config file %s: %w", path, err)
+			}
+		}
+
+		for k := range values {
+			set[k] = true
+		}
+	}
+
+	// the environment may have been set by one of the files
+	applyEnvDefaults(&c, func(path string) bool { return set[path] })
+
+	if err := c.Validate(); err != nil {
+		return C{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return c, nil
+}
+
+// decodeConfigFile decodes the config file contents b, based on the extension
+// of path, and flattens it to a map of Set paths to values.
+func decodeConfigFile(path string, b []byte) (map[string]string, error) {
+	var m map[string]interface{}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json", ".jsonc":
+		b, err := stripJSONC(b)
+		if err != nil {
+			return nil, err
+		}
+
+		// UseNumber keeps large integers from being formatted as floats
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+
+		if err := dec.Decode(&m); err != nil {
+			if line, ok := jsonErrorLine(b, err); ok {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+
+			return nil, err
+		}
+
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(b, &m); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q", ext)
+	}
+
+	values := make(map[string]string)
+
+	if err := flattenConfig(values, "", m); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// flattenConfig adds each value in the decoded mapping m to values, keyed by
+// its dotted path under prefix.
+func flattenConfig(values map[string]string, prefix string, m map[string]interface{}) error {
+	for k, v := range m {
+		path := prefix + k
+
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if err := flattenConfig(values, path+".", v); err != nil {
+				return err
+			}
+
+		case map[interface{}]interface{}: // yaml.v2's nested mappings
+			sm := make(map[string]interface{}, len(v))
+			for mk, mv := range v {
+				sm[fmt.Sprint(mk)] = mv
+			}
+
+			if err := flattenConfig(values, path+".", sm); err != nil {
+				return err
+			}
+
+		case []interface{}:
+			items := make([]string, len(v))
+
+			for i, item := range v {
+				switch item.(type) {
+				case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+					return fmt.Errorf("%s must be a list of values", path)
+				}
+
+				items[i] = fmt.Sprint(item)
+			}
+
+			values[path] = strings.Join(items, ",")
+
+		case nil:
+			values[path] = ""
+
+		default:
+			values[path] = fmt.Sprint(v)
+		}
+	}
+
+	return nil
+}
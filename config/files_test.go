@@ -0,0 +1,169 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rs/zerolog"
+)
+
+func TestLoadFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		paths []string
+		want  C
+		err   string
+	}{
+		{
+			name: "layered",
+			files: map[string]string{
+				"base.yaml": "log_level: warn\nport: 8080\nmax_request_bytes: 2097152\nslack:\n  team_id: T12345\n  max_timestamp_skew: 1m\n",
+				"staging.json": `{
+	// staging overrides
+	"env": "staging",
+	"slack": {"team_id": "T67890",},
+}`,
+				"local.yml": "redis:\n  tls_cipher_suites: [TLS_AES_128_GCM_SHA256, TLS_AES_256_GCM_SHA384]\n",
+			},
+			paths: []string{"base.yaml", "staging.json", "missing.yaml", "local.yml"},
+			want: C{
+				LogLevel:                 zerolog.WarnLevel,
+				Env:                      Staging,
+				Port:                     8080,
+				MaxRequestBytes:          2 << 20,
+				HealthPath:               "/healthz",
+				Timezone:                 "UTC",
+				Locale:                   "en-US",
+				MaxConcurrentEvents:      runtime.NumCPU() * 4,
+				RedisFailMode:            RedisFailClosed,
+				MaxMessageLength:         40000,
+				MaxBlocksPerMessage:      50,
+				RedisPoolMetricsInterval: 15 * time.Second,
+				AuditLogLevel:            zerolog.InfoLevel,
+				MaintenanceMessage:       defaultMaintenanceMessage,
+				Redis:                    R{DB: 1, TLSCipherSuites: []string{"TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384"}},
+				Slack: S{
+					TeamID:            "T67890",
+					MaxTimestampSkew:  time.Minute,
+					AckMode:           AckModeImmediate,
+					AckTimeout:        2500 * time.Millisecond,
+					MaxRetryNum:       3,
+					CommandPrefix:     "!",
+					RespondToMentions: true,
+				},
+			},
+		},
+		{
+			name:  "missing_first",
+			paths: []string{"base.yaml"},
+			err:   "failed to read config file",
+		},
+		{
+			name:  "unsupported_extension",
+			files: map[string]string{"base.toml": "port = 8080\n"},
+			paths: []string{"base.toml"},
+			err:   `unsupported config file extension ".toml"`,
+		},
+		{
+			name:  "unknown_key",
+			files: map[string]string{"base.yaml": "prot: 8080\n"},
+			paths: []string{"base.yaml"},
+			err:   `unknown configuration path "prot"`,
+		},
+		{
+			name:  "bad_value",
+			files: map[string]string{"base.json": "{\"slack\": {\"max_timestamp_skew\": 60}}"},
+			paths: []string{"base.json"},
+			err:   "failed to set slack.max_timestamp_skew",
+		},
+		{
+			name:  "unknown_env",
+			files: map[string]string{"base.yaml": "env: prod\n"},
+			paths: []string{"base.yaml"},
+			err:   `failed to set env: unknown environment "prod"`,
+		},
+		{
+			name:  "production_capitalized",
+			files: map[string]string{"base.yaml": "env: Production\n"},
+			paths: []string{"base.yaml"},
+			err:   "one of Slack.RequestSecret or Slack.RequestToken is required in production",
+		},
+		{
+			name:  "invalid",
+			files: map[string]string{"base.yaml": "max_message_length: 0\n"},
+			paths: []string{"base.yaml"},
+			err:   "invalid configuration: MaxMessageLength must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "gopher-config")
+			testErrCheck(t, "ioutil.TempDir()", "", err)
+
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			for name, contents := range tt.files {
+				err = ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600)
+				testErrCheck(t, "ioutil.WriteFile()", "", err)
+			}
+
+			paths := make([]string, len(tt.paths))
+			for i, p := range tt.paths {
+				paths[i] = filepath.Join(dir, p)
+			}
+
+			got, err := LoadFiles(paths...)
+			if cont := testErrCheck(t, "LoadFiles()", tt.err, err); !cont {
+				return
+			}
+
+			cmpDiff(t, "C", cmp.Diff(tt.want, got))
+		})
+	}
+}
+
+func TestLoadFiles_envDefaults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopher-config")
+	testErrCheck(t, "ioutil.TempDir()", "", err)
+
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	base, prod := filepath.Join(dir, "base.yaml"), filepath.Join(dir, "production.yaml")
+
+	testErrCheck(t, "ioutil.WriteFile()", "", ioutil.WriteFile(base, []byte("slack:\n  request_secret: abc\n"), 0o600))
+	testErrCheck(t, "ioutil.WriteFile()", "", ioutil.WriteFile(prod, []byte("env: production\n"), 0o600))
+
+	c, err := LoadFiles(base, prod)
+	testErrCheck(t, "LoadFiles()", "", err)
+
+	if !c.MetricsEnabled || c.Redis.DB != 0 || c.Slack.Disabled {
+		t.Fatalf("LoadFiles() MetricsEnabled/Redis.DB/Slack.Disabled = %t/%d/%t, want production's true/0/false", c.MetricsEnabled, c.Redis.DB, c.Slack.Disabled)
+	}
+
+	// a file that sets it explicitly wins
+	testErrCheck(t, "ioutil.WriteFile()", "", ioutil.WriteFile(prod, []byte("env: production\nmetrics_enabled: false\n"), 0o600))
+
+	c, err = LoadFiles(base, prod)
+	testErrCheck(t, "LoadFiles()", "", err)
+
+	if c.MetricsEnabled {
+		t.Fatal("LoadFiles() MetricsEnabled = true, want the file's false")
+	}
+
+	// the environment is matched ignoring case, like ENV
+	testErrCheck(t, "ioutil.WriteFile()", "", ioutil.WriteFile(prod, []byte("env: Production\n"), 0o600))
+
+	c, err = LoadFiles(base, prod)
+	testErrCheck(t, "LoadFiles()", "", err)
+
+	if c.Env != Production || !c.MetricsEnabled || c.Redis.DB != 0 {
+		t.Fatalf("LoadFiles() Env/MetricsEnabled/Redis.DB = %s/%t/%d, want production/true/0", c.Env, c.MetricsEnabled, c.Redis.DB)
+	}
+}
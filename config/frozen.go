@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Frozen is a read-only view of a configuration, returned by C.Freeze. Its
+// getters return copies, so there's no way to change the configuration
+// through it.
+//
+// Slices in the returned copies still share their backing arrays, so in
+// Development a Frozen also keeps a private copy of the configuration and
+// panics on access if the two no longer match. In other environments that
+// check is skipped, making Frozen a zero-cost passthrough.
+type Frozen struct {
+	c        C
+	pristine *C
+}
+
+// Freeze returns a read-only view of the configuration, to enforce the "load
+// once, treat as read-only" contract.
+func (c C) Freeze() *Frozen {
+	f := &Frozen{c: c}
+
+	if c.Env == Development {
+		p := c.clone()
+		f.pristine = &p
+	}
+
+	return f
+}
+
+// check panics if the configuration was changed after Freeze, which can only
+// be detected in Development.
+func (f *Frozen) check() {
+	if f.pristine == nil {
+		return
+	}
+
+	if d := Diff(*f.pristine, f.c); len(d) > 0 {
+		panic(fmt.Sprintf("config: frozen configuration was mutated: %s", strings.Join(d, ", ")))
+	}
+}
+
+// Config returns a copy of the full configuration.
+func (f *Frozen) Config() C { f.check(); return f.c }
+
+// Env returns the current environment.
+func (f *Frozen) Env() Environment { f.check(); return f.c.Env }
+
+// Port returns the TCP port to listen on.
+func (f *Frozen) Port() uint16 { f.check(); return f.c.Port }
+
+// Heroku returns a copy of the Heroku configuration.
+func (f *Frozen) Heroku() H { f.check(); return f.c.Heroku }
+
+// Redis returns a copy of the Redis configuration.
+func (f *Frozen) Redis() R { f.check(); return f.c.Redis }
+
+// Slack returns a copy of the Slack configuration.
+func (f *Frozen) Slack() S { f.check(); return f.c.Slack }
+
+// clone returns a deep copy of the configuration, so that slices and maps
+// don't share their backing storage with c.
+func (c C) clone() C {
+	cloneSlices(reflect.ValueOf(&c).Elem())
+	return c
+}
+
+func cloneSlices(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		fv := v.Field(i)
+
+		switch fv.Kind() {
+		case reflect.Struct:
+			cloneSlices(fv)
+
+		case reflect.Slice:
+			if fv.IsNil() {
+				continue
+			}
+
+			s := reflect.MakeSlice(fv.Type(), fv.Len(), fv.Len())
+			reflect.Copy(s, fv)
+			fv.Set(s)
+
+		case reflect.Map:
+			if fv.IsNil() {
+				continue
+			}
+
+			m := reflect.MakeMapWithSize(fv.Type(), fv.Len())
+			for _, k := range fv.MapKeys() {
+				m.SetMapIndex(k, fv.MapIndex(k))
+			}
+
+			fv.Set(m)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestC_Freeze(t *testing.T) {
+	c := C{
+		Env:   Development,
+		Port:  8080,
+		Redis: R{TLSCipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}},
+		Slack: S{TeamID: "T123"},
+	}
+
+	t.Run("getters", func(t *testing.T) {
+		f := c.Freeze()
+
+		cmpDiff(t, "Config()", cmp.Diff(c, f.Config()))
+
+		if f.Env() != Development || f.Port() != 8080 || f.Slack().TeamID != "T123" {
+			t.Fatal("getters returned unexpected values")
+		}
+
+		// changing a copy doesn't change the frozen config
+		s := f.Slack()
+		s.TeamID = "T456"
+
+		if f.Slack().TeamID != "T123" {
+			t.Fatal("Slack() returned a reference to the frozen config")
+		}
+	})
+
+	t.Run("mutation_development", func(t *testing.T) {
+		f := c.clone().Freeze()
+		f.Redis().TLSCipherSuites[0] = "TLS_MADE_UP"
+
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic after mutation")
+			}
+		}()
+
+		_ = f.Port()
+	})
+
+	t.Run("mutation_production", func(t *testing.T) {
+		pc := c.clone()
+		pc.Env = Production
+
+		f := pc.Freeze()
+		f.Redis().TLSCipherSuites[0] = "TLS_MADE_UP"
+
+		_ = f.Port() // no check outside of Development
+	})
+}
+
+func TestC_clone(t *testing.T) {
+	c := C{Redis: R{TLSCipherSuites: []string{"a", "b"}}}
+	cc := c.clone()
+
+	cc.Redis.TLSCipherSuites[0] = "z"
+
+	if c.Redis.TLSCipherSuites[0] != "a" {
+		t.Fatal("clone() shares slice backing arrays with the original")
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-redis/redis"
+)
+
+// CheckError is the error returned by a check built with NamedCheck, so that
+// the HealthHandler can report which check failed.
+type CheckError struct {
+	// Name is the name of the failing check
+	Name string
+
+	// Err is the error returned by the check
+	Err error
+}
+
+func (e *CheckError) Error() string {
+	return fmt.Sprintf("%s check failed: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the error returned by the check.
+func (e *CheckError) Unwrap() error {
+	return e.Err
+}
+
+// NamedCheck wraps the health check so that any error it returns is a
+// *CheckError with the given name.
+func NamedCheck(name string, check func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if err := check(ctx); err != nil {
+			return &CheckError{Name: name, Err: err}
+		}
+
+		return nil
+	}
+}
+
+// CheckRedis returns a health check, named "redis", that PINGs the Redis
+// server.
+func CheckRedis(client *redis.Client) func(context.Context) error {
+	return NamedCheck("redis", func(ctx context.Context) error {
+		return client.WithContext(ctx).Ping().Err()
+	})
+}
+
+type healthResponse struct {
+	Status string `json:"status"`
+	Commit string `json:"commit,omitempty"`
+	Check  string `json:"check,omitempty"`
+}
+
+// HealthHandler returns an http.Handler, meant to be served from
+// c.HealthPath, that runs each of the checks in order. If they all pass it
+// responds with 200 OK, otherwise it responds with 503 Service Unavailable and
+// the name of the first failing check. The response body is JSON, and always
+// includes the short commit from the Heroku metadata.
+//
+// Checks built with NamedCheck (such as CheckRedis) are reported by name,
+// while others are reported by their position (e.g., "check 2"). The check's
+// error itself isn't included, as it could contain internal details.
+func (c C) HealthHandler(checks ...func(context.Context) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := healthResponse{
+			Status: "ok",
+			Commit: c.Heroku.ShortCommit(),
+		}
+
+		code := http.StatusOK
+
+		for i, check := range checks {
+			err := check(r.Context())
+			if err == nil {
+				continue
+			}
+
+			var ce *CheckError
+
+			resp.Status = "unavailable"
+			resp.Check = fmt.Sprintf("check %d", i+1)
+			code = http.StatusServiceUnavailable
+
+			if errors.As(err, &ce) {
+				resp.Check = ce.Name
+			}
+
+			break
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
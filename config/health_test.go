@@ -0,0 +1,105 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-redis/redis"
+)
+
+func TestH_ShortCommit(t *testing.T) {
+	tests := []struct {
+		commit string
+		want   string
+	}{
+		{commit: "", want: ""},
+		{commit: "abc", want: "abc"},
+		{commit: "deadbeefcafe", want: "deadbee"},
+	}
+
+	for _, tt := range tests {
+		if got := (H{Commit: tt.commit}).ShortCommit(); got != tt.want {
+			t.Errorf("ShortCommit(%q) = %q, want %q", tt.commit, got, tt.want)
+		}
+	}
+}
+
+func TestC_HealthHandler(t *testing.T) {
+	pass := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name     string
+		checks   []func(context.Context) error
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "no_checks",
+			wantCode: http.StatusOK,
+			wantBody: `{"status":"ok","commit":"deadbee"}`,
+		},
+		{
+			name:     "passing",
+			checks:   []func(context.Context) error{pass, NamedCheck("db", pass)},
+			wantCode: http.StatusOK,
+			wantBody: `{"status":"ok","commit":"deadbee"}`,
+		},
+		{
+			name:     "failing_named",
+			checks:   []func(context.Context) error{pass, NamedCheck("db", fail), NamedCheck("other", fail)},
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: `{"status":"unavailable","commit":"deadbee","check":"db"}`,
+		},
+		{
+			name:     "failing_unnamed",
+			checks:   []func(context.Context) error{pass, fail},
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: `{"status":"unavailable","commit":"deadbee","check":"check 2"}`,
+		},
+	}
+
+	c := C{Heroku: H{Commit: "deadbeefcafe"}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			c.HealthHandler(tt.checks...).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status code = %d, want %d", w.Code, tt.wantCode)
+			}
+
+			if got := strings.TrimSpace(w.Body.String()); got != tt.wantBody {
+				t.Fatalf("body = %s, want %s", got, tt.wantBody)
+			}
+
+			if strings.Contains(w.Body.String(), "connection refused") {
+				t.Fatal("body should not contain the check error")
+			}
+		})
+	}
+}
+
+func TestCheckRedis(t *testing.T) {
+	f := newFakeRedis(t)
+	defer f.Close()
+
+	rc := redis.NewClient(DefaultRedis(C{Redis: R{Addr: f.Addr(), Insecure: true}}))
+	defer func() { _ = rc.Close() }()
+
+	testErrCheck(t, "CheckRedis()", "", CheckRedis(rc)(context.Background()))
+
+	f.Close()
+	_ = rc.Close()
+
+	var ce *CheckError
+	if err := CheckRedis(rc)(context.Background()); !errors.As(err, &ce) || ce.Name != "redis" {
+		t.Fatalf("CheckRedis() error = %v, want *CheckError named redis", err)
+	}
+}
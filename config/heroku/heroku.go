@@ -0,0 +1,122 @@
+// Package heroku fetches release information about the running app from the
+// Heroku Platform API, to enrich what the Labs Dyno Metadata (config.H)
+// provides. It makes network calls, so it's meant for things like deploy
+// notifications and not the request path.
+package heroku
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gobridge/gopherbot/config"
+)
+
+// DefaultBaseURL is the Heroku Platform API URL.
+const DefaultBaseURL = "https://api.heroku.com"
+
+// Release is a release of the app.
+type Release struct {
+	// Version is the release's version number (e.g., 42 for v42)
+	Version int
+
+	// Description is the release's description, like "Deploy deadbee"
+	Description string
+
+	// User is the email address of the user who created the release
+	User string
+
+	// CreatedAt is when the release was created
+	CreatedAt time.Time
+}
+
+// Client is a minimal Heroku Platform API client, supporting only what's needed
+// to read releases.
+type Client struct {
+	// BaseURL is the Platform API URL, defaulting to DefaultBaseURL
+	BaseURL string
+
+	// APIKey is the API key used to authenticate with the Platform API
+	APIKey string
+
+	// HTTPClient is the HTTP client used for requests, defaulting to one
+	// with a 10 second timeout
+	HTTPClient *http.Client
+}
+
+// release is the subset of the Platform API's release object that we use.
+type release struct {
+	Version     int       `json:"version"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	User        struct {
+		Email string `json:"email"`
+	} `json:"user"`
+}
+
+// LatestRelease returns the most recent release of the app h.AppID.
+func (c *Client) LatestRelease(ctx context.Context, h config.H) (Release, error) {
+	if len(h.AppID) == 0 {
+		return Release{}, errors.New("Heroku.AppID is not set")
+	}
+
+	base, hc := c.BaseURL, c.HTTPClient
+	if len(base) == 0 {
+		base = DefaultBaseURL
+	}
+
+	if hc == nil {
+		hc = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, base+"/apps/"+url.PathEscape(h.AppID)+"/releases", nil)
+	if err != nil {
+		return Release{}, err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/vnd.heroku+json; version=3")
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Range", "version ..; order=desc, max=1")
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to get releases: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// the API responds with 206 Partial Content when there are more releases
+	// than the Range returned
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return Release{}, fmt.Errorf("failed to get releases: unexpected HTTP status: %s", resp.Status)
+	}
+
+	var rs []release
+
+	if err := json.NewDecoder(resp.Body).Decode(&rs); err != nil {
+		return Release{}, fmt.Errorf("failed to decode releases: %w", err)
+	}
+
+	if len(rs) == 0 {
+		return Release{}, fmt.Errorf("app %s has no releases", h.AppID)
+	}
+
+	return Release{
+		Version:     rs[0].Version,
+		Description: rs[0].Description,
+		User:        rs[0].User.Email,
+		CreatedAt:   rs[0].CreatedAt,
+	}, nil
+}
+
+// FetchRelease returns the most recent release of the app described by h,
+// using apiKey to authenticate with the Platform API.
+func FetchRelease(ctx context.Context, h config.H, apiKey string) (Release, error) {
+	c := &Client{APIKey: apiKey}
+	return c.LatestRelease(ctx, h)
+}
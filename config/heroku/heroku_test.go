@@ -0,0 +1,63 @@
+package heroku
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobridge/gopherbot/config"
+)
+
+func TestClient_LatestRelease(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path != "/apps/abc123/releases" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if got := r.Header.Get("Range"); got != "version ..; order=desc, max=1" {
+			t.Errorf("Range = %q", got)
+		}
+
+		w.WriteHeader(http.StatusPartialContent)
+		fmt.Fprint(w, `[{"version":42,"description":"Deploy deadbee","created_at":"2020-04-01T12:00:00Z","user":{"email":"gopher@example.org"}}]`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL, APIKey: "test-key", HTTPClient: srv.Client()}
+
+	r, err := c.LatestRelease(context.Background(), config.H{AppID: "abc123"})
+	if err != nil {
+		t.Fatalf("LatestRelease() unexpected error: %v", err)
+	}
+
+	want := Release{
+		Version:     42,
+		Description: "Deploy deadbee",
+		User:        "gopher@example.org",
+		CreatedAt:   time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	if r != want {
+		t.Fatalf("LatestRelease() = %+v, want %+v", r, want)
+	}
+
+	_, err = c.LatestRelease(context.Background(), config.H{AppID: "missing"})
+	if err == nil || !strings.Contains(err.Error(), "unexpected HTTP status: 404 Not Found") {
+		t.Fatalf("LatestRelease() error = %v, want not found error", err)
+	}
+
+	_, err = c.LatestRelease(context.Background(), config.H{})
+	if err == nil || err.Error() != "Heroku.AppID is not set" {
+		t.Fatalf("LatestRelease() error = %v, want AppID error", err)
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Holder holds the current configuration for loaders that refresh it while the
+// process runs, such as ones leasing secrets from Vault or IAM. Those leases
+// expire, and it's safer to shut down than to carry on with credentials that
+// no longer work, so loaders register when the configuration goes stale with
+// ExpireAt and the OnExpire callback is called if it isn't refreshed in time.
+type Holder struct {
+	logger zerolog.Logger
+
+	mu       sync.Mutex
+	c        C
+	onExpire func(expiredAt time.Time)
+	timer    *time.Timer
+	gen      uint64
+}
+
+// NewHolder returns a Holder for c. Until OnExpire is set, an expiry is only
+// logged to logger as a warning.
+func NewHolder(c C, logger zerolog.Logger) *Holder {
+	return &Holder{c: c, logger: logger}
+}
+
+// Config returns the current configuration.
+func (h *Holder) Config() C {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.c
+}
+
+// Store replaces the current configuration with c. It doesn't change when the
+// configuration expires, so call ExpireAt with the new lease's expiry too.
+func (h *Holder) Store(c C) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.c = c
+}
+
+// OnExpire sets the func called when the configuration expires, such as one
+// that starts a graceful shutdown. It's called from its own goroutine.
+func (h *Holder) OnExpire(f func(expiredAt time.Time)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.onExpire = f
+}
+
+// ExpireAt sets when the configuration expires, replacing any earlier expiry.
+// A time in the past expires it right away, and the zero time means it doesn't
+// expire.
+func (h *Holder) ExpireAt(t time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+
+	if t.IsZero() {
+		return
+	}
+
+	h.gen++
+	gen := h.gen
+	h.timer = time.AfterFunc(t.Sub(clock.Now()), func() { h.expire(gen, t) })
+}
+
+// expire calls the OnExpire callback, unless the expiry numbered gen was
+// replaced by a later call to ExpireAt while its timer was firing.
+func (h *Holder) expire(gen uint64, t time.Time) {
+	h.mu.Lock()
+
+	if h.timer == nil || h.gen != gen {
+		h.mu.Unlock()
+		return
+	}
+
+	h.timer = nil
+	f := h.onExpire
+
+	h.mu.Unlock()
+
+	if f == nil {
+		h.logger.Warn().
+			Time("expired_at", t).
+			Msg("configuration expired without being refreshed, but no OnExpire callback is set")
+
+		return
+	}
+
+	f(t)
+}
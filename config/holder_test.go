@@ -0,0 +1,113 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestHolder(t *testing.T) {
+	h := NewHolder(C{Port: 1234}, zerolog.Nop())
+
+	if h.Config().Port != 1234 {
+		t.Fatalf("Config().Port = %d, want 1234", h.Config().Port)
+	}
+
+	h.Store(C{Port: 4321})
+
+	if h.Config().Port != 4321 {
+		t.Fatalf("Config().Port = %d after Store, want 4321", h.Config().Port)
+	}
+}
+
+func TestHolder_ExpireAt(t *testing.T) {
+	t.Run("expires", func(t *testing.T) {
+		h := NewHolder(C{}, zerolog.Nop())
+
+		expired := make(chan time.Time, 1)
+		h.OnExpire(func(at time.Time) { expired <- at })
+
+		at := time.Now().Add(10 * time.Millisecond)
+		h.ExpireAt(at)
+
+		select {
+		case got := <-expired:
+			if !got.Equal(at) {
+				t.Fatalf("OnExpire called with %s, want %s", got, at)
+			}
+
+		case <-time.After(time.Second):
+			t.Fatal("OnExpire wasn't called")
+		}
+	})
+
+	t.Run("refreshed", func(t *testing.T) {
+		h := NewHolder(C{}, zerolog.Nop())
+
+		expired := make(chan time.Time, 2)
+		h.OnExpire(func(at time.Time) { expired <- at })
+
+		h.ExpireAt(time.Now().Add(10 * time.Millisecond))
+
+		// the lease was renewed before it expired
+		later := time.Now().Add(time.Hour)
+		h.ExpireAt(later)
+
+		select {
+		case at := <-expired:
+			t.Fatalf("OnExpire called for the replaced expiry %s", at)
+
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		h.ExpireAt(time.Time{})
+	})
+
+	t.Run("default_warns", func(t *testing.T) {
+		var (
+			mu  sync.Mutex
+			buf bytes.Buffer
+		)
+
+		w := zerolog.SyncWriter(&lockedWriter{mu: &mu, w: &buf})
+		h := NewHolder(C{}, zerolog.New(w))
+
+		h.ExpireAt(time.Now().Add(-time.Second))
+
+		deadline := time.Now().Add(time.Second)
+
+		for {
+			mu.Lock()
+			s := buf.String()
+			mu.Unlock()
+
+			if strings.Contains(s, `"level":"warn"`) && strings.Contains(s, "no OnExpire callback is set") {
+				break
+			}
+
+			if time.Now().After(deadline) {
+				t.Fatalf("expiry wasn't logged as a warning, log = %s", s)
+			}
+
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+}
+
+// lockedWriter guards writes to w with mu, so the test can read what's been
+// written while the logger might still be writing.
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  *bytes.Buffer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.w.Write(p)
+}
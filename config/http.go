@@ -0,0 +1,182 @@
+package config
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// defaultPanicStackDepth is the number of stack frames RecoveryMiddleware logs
+// if PanicStackDepth isn't set.
+const defaultPanicStackDepth = 32
+
+// DefaultHTTPServer returns an *http.Server for h using our default timeouts.
+// If TLS is configured the server requires TLS 1.2 or newer, and should be
+// started with Serve so that it uses the configured certificate. If HTTP2 is
+// enabled, the server also accepts cleartext HTTP/2 (h2c) connections.
+func DefaultHTTPServer(cfg C, h http.Handler) *http.Server {
+	srv := &http.Server{
+		Handler:        h,
+		ReadTimeout:    20 * time.Second,
+		WriteTimeout:   cfg.HTTPWriteTimeout,
+		IdleTimeout:    60 * time.Second,
+		MaxHeaderBytes: cfg.MaxHeaderBytes,
+	}
+
+	if cfg.TLS.Enabled() {
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	if cfg.HTTP2 && !cfg.TLS.Enabled() {
+		srv.Handler = h2c.NewHandler(h, &http2.Server{
+			IdleTimeout: srv.IdleTimeout,
+		})
+	}
+
+	return srv
+}
+
+// Serve accepts connections on l using srv, serving TLS with the configured
+// certificate and key if TLS is enabled, and plaintext HTTP otherwise.
+func (c C) Serve(srv *http.Server, l net.Listener) error {
+	if c.TLS.Enabled() {
+		return srv.ServeTLS(l, c.TLS.CertFile, c.TLS.KeyFile)
+	}
+
+	return srv.Serve(l)
+}
+
+// LimitBody wraps h so that request bodies larger than c.MaxRequestBytes fail
+// to be read, using http.MaxBytesReader. This protects handlers from clients
+// sending us oversized payloads.
+func (c C) LimitBody(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, c.MaxRequestBytes)
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// DebugHandler returns an http.Handler that serves the redacted configuration
+// as JSON, for inspecting the configuration of a running process. Requests
+// must provide the token as a bearer token in the Authorization header. If
+// token is empty c.DebugToken is used instead, and if that's also empty the
+// handler responds with 404 Not Found to every request so that the endpoint
+// is invisible unless it's explicitly enabled.
+func (c C) DebugHandler(token string) http.Handler {
+	if len(token) == 0 {
+		token = c.DebugToken
+	}
+
+	body, err := json.Marshal(c.Redacted())
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(token) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		auth := r.Header.Get("Authorization")
+
+		if !strings.HasPrefix(auth, "Bearer ") ||
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, _ = w.Write(body)
+	})
+}
+
+// RecoveryMiddleware returns middleware that recovers from panics in the
+// handler, logs them with a stack trace, and responds with 500 Internal Server
+// Error. In Development the full stack trace is logged as is, to make it easy
+// to read locally. In other environments it's logged as a structured list of
+// up to c.PanicStackDepth frames.
+//
+// Panics with http.ErrAbortHandler are not recovered, so the server can abort
+// the response as intended.
+func (c C) RecoveryMiddleware(logger zerolog.Logger) func(http.Handler) http.Handler {
+	depth := c.PanicStackDepth
+	if depth <= 0 {
+		depth = defaultPanicStackDepth
+	}
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				e := logger.Error().
+					Str("panic", fmt.Sprint(rec)).
+					Str("method", r.Method).
+					Str("path", r.URL.Path)
+
+				if c.Env == Development {
+					e = e.Str("stack", string(debug.Stack()))
+				} else {
+					e = e.Strs("stack", stackFrames(depth))
+				}
+
+				e.Msg("recovered from panic in HTTP handler")
+
+				w.WriteHeader(http.StatusInternalServerError)
+			}()
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// stackFrames returns up to depth frames of the panicking goroutine's stack,
+// formatted as "function file:line". It must be called from the deferred
+// function that recovered the panic. Frames within the runtime, including
+// the panic machinery itself, are skipped.
+func stackFrames(depth int) []string {
+	pcs := make([]uintptr, depth+16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	s := make([]string, 0, depth)
+
+	for len(s) < depth {
+		f, more := frames.Next()
+
+		if !strings.HasPrefix(f.Function, "runtime.") {
+			s = append(s, fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line))
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,387 @@
+package config
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/net/http2"
+)
+
+func TestC_LimitBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  string
+	}{
+		{
+			name: "under_limit",
+			body: "abc",
+		},
+		{
+			name: "at_limit",
+			body: "abcd",
+		},
+		{
+			name: "over_limit",
+			body: "abcde",
+			err:  "http: request body too large",
+		},
+	}
+
+	c := C{MaxRequestBytes: 4}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			var err error
+
+			h := c.LimitBody(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var b []byte
+				b, err = ioutil.ReadAll(r.Body)
+				got = string(b)
+			}))
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+
+			if cont := testErrCheck(t, "ioutil.ReadAll()", tt.err, err); !cont {
+				return
+			}
+
+			if got != tt.body {
+				t.Fatalf("body = %q, want %q", got, tt.body)
+			}
+		})
+	}
+}
+
+func TestC_DebugHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		configToken  string
+		handlerToken string
+		auth         string
+		wantCode     int
+	}{
+		{
+			name:     "disabled",
+			auth:     "Bearer ",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:        "missing_auth",
+			configToken: "debug123",
+			wantCode:    http.StatusUnauthorized,
+		},
+		{
+			name:        "wrong_scheme",
+			configToken: "debug123",
+			auth:        "Basic debug123",
+			wantCode:    http.StatusUnauthorized,
+		},
+		{
+			name:        "wrong_token",
+			configToken: "debug123",
+			auth:        "Bearer debug12",
+			wantCode:    http.StatusUnauthorized,
+		},
+		{
+			name:        "config_token",
+			configToken: "debug123",
+			auth:        "Bearer debug123",
+			wantCode:    http.StatusOK,
+		},
+		{
+			name:         "handler_token_overrides",
+			configToken:  "debug123",
+			handlerToken: "other456",
+			auth:         "Bearer other456",
+			wantCode:     http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := C{
+				Env:        Testing,
+				Slack:      S{TeamID: "T123", BotAccessToken: "xoxb-123"},
+				DebugToken: tt.configToken,
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/debug/config", nil)
+			if len(tt.auth) > 0 {
+				r.Header.Set("Authorization", tt.auth)
+			}
+
+			w := httptest.NewRecorder()
+
+			c.DebugHandler(tt.handlerToken).ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status code = %d, want %d", w.Code, tt.wantCode)
+			}
+
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			body := w.Body.String()
+
+			if !strings.Contains(body, `"team_id":"T123"`) {
+				t.Fatalf("body = %s, should contain team_id", body)
+			}
+
+			if strings.Contains(body, "xoxb-123") || strings.Contains(body, tt.configToken) {
+				t.Fatalf("body = %s, should not contain secrets", body)
+			}
+		})
+	}
+}
+
+func TestC_RecoveryMiddleware(t *testing.T) {
+	panicky := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	t.Run("development", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		c := C{Env: Development}
+		rr := httptest.NewRecorder()
+
+		c.RecoveryMiddleware(zerolog.New(&buf))(panicky).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+		if rr.Code != http.StatusInternalServerError {
+			t.Fatalf("rr.Code = %d, want %d", rr.Code, http.StatusInternalServerError)
+		}
+
+		var entry struct {
+			Panic string `json:"panic"`
+			Path  string `json:"path"`
+			Stack string `json:"stack"`
+		}
+
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("failed to unmarshal log entry %q: %v", buf.String(), err)
+		}
+
+		if entry.Panic != "boom" || entry.Path != "/boom" {
+			t.Fatalf("log entry = %+v, want panic boom at /boom", entry)
+		}
+
+		if !strings.Contains(entry.Stack, "goroutine") {
+			t.Fatalf("stack = %q, want full stack trace", entry.Stack)
+		}
+	})
+
+	t.Run("production", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		c := C{Env: Production, PanicStackDepth: 2}
+		rr := httptest.NewRecorder()
+
+		c.RecoveryMiddleware(zerolog.New(&buf))(panicky).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+		if rr.Code != http.StatusInternalServerError {
+			t.Fatalf("rr.Code = %d, want %d", rr.Code, http.StatusInternalServerError)
+		}
+
+		var entry struct {
+			Stack []string `json:"stack"`
+		}
+
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("failed to unmarshal log entry %q: %v", buf.String(), err)
+		}
+
+		if len(entry.Stack) != 2 {
+			t.Fatalf("len(stack) = %d, want 2: %q", len(entry.Stack), entry.Stack)
+		}
+
+		if !strings.Contains(entry.Stack[0], "TestC_RecoveryMiddleware") {
+			t.Fatalf("stack[0] = %q, want the panicking handler", entry.Stack[0])
+		}
+	})
+
+	t.Run("no_panic", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		rr := httptest.NewRecorder()
+
+		C{}.RecoveryMiddleware(zerolog.New(&buf))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rr.Code != http.StatusTeapot || buf.Len() > 0 {
+			t.Fatalf("rr.Code = %d, log = %q, want %d and no log", rr.Code, buf.String(), http.StatusTeapot)
+		}
+	})
+}
+
+// writeTestCert writes a self-signed certificate for 127.0.0.1 and its key to
+// dir, returning their paths.
+func writeTestCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testErrCheck(t, "ecdsa.GenerateKey()", "", err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "gopher test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	testErrCheck(t, "x509.CreateCertificate()", "", err)
+
+	kb, err := x509.MarshalECPrivateKey(key)
+	testErrCheck(t, "x509.MarshalECPrivateKey()", "", err)
+
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	testErrCheck(t, "ioutil.WriteFile()", "", err)
+
+	err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kb}), 0600)
+	testErrCheck(t, "ioutil.WriteFile()", "", err)
+
+	return certFile, keyFile
+}
+
+func TestC_Serve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopher-tls")
+	testErrCheck(t, "ioutil.TempDir()", "", err)
+
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	certFile, keyFile := writeTestCert(t, dir)
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})
+
+	tests := []struct {
+		name   string
+		tls    T
+		scheme string
+	}{
+		{
+			name:   "plaintext",
+			scheme: "http",
+		},
+		{
+			name:   "tls",
+			tls:    T{CertFile: certFile, KeyFile: keyFile},
+			scheme: "https",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := C{TLS: tt.tls}
+
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			testErrCheck(t, "net.Listen()", "", err)
+
+			srv := DefaultHTTPServer(c, h)
+			defer func() { _ = srv.Close() }()
+
+			if (srv.TLSConfig != nil) != tt.tls.Enabled() {
+				t.Fatalf("srv.TLSConfig = %v, want TLS %t", srv.TLSConfig, tt.tls.Enabled())
+			}
+
+			go func() { _ = c.Serve(srv, l) }()
+
+			client := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // #nosec G402 -- self-signed test certificate
+				},
+			}
+
+			resp, err := client.Get(tt.scheme + "://" + l.Addr().String())
+			testErrCheck(t, "client.Get()", "", err)
+
+			defer func() { _ = resp.Body.Close() }()
+
+			if (resp.TLS != nil) != tt.tls.Enabled() {
+				t.Fatalf("resp.TLS = %v, want TLS %t", resp.TLS, tt.tls.Enabled())
+			}
+		})
+	}
+}
+
+func TestDefaultHTTPServer_http2(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	})
+
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("enabled_%t", enabled), func(t *testing.T) {
+			c := C{HTTP2: enabled, MaxHeaderBytes: 4096}
+
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			testErrCheck(t, "net.Listen()", "", err)
+
+			srv := DefaultHTTPServer(c, h)
+			defer func() { _ = srv.Close() }()
+
+			if srv.MaxHeaderBytes != 4096 {
+				t.Fatalf("srv.MaxHeaderBytes = %d, want 4096", srv.MaxHeaderBytes)
+			}
+
+			go func() { _ = c.Serve(srv, l) }()
+
+			// h2c clients use prior knowledge, dialing plaintext for https URLs
+			client := &http.Client{
+				Transport: &http2.Transport{
+					AllowHTTP: true,
+					DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+						return net.Dial(network, addr)
+					},
+				},
+			}
+
+			resp, err := client.Get("http://" + l.Addr().String())
+			if !enabled {
+				if err == nil {
+					_ = resp.Body.Close()
+					t.Fatal("client.Get() succeeded over HTTP/2 with HTTP2 disabled")
+				}
+
+				return
+			}
+
+			testErrCheck(t, "client.Get()", "", err)
+
+			defer func() { _ = resp.Body.Close() }()
+
+			b, _ := ioutil.ReadAll(resp.Body)
+
+			if string(b) != "HTTP/2.0" {
+				t.Fatalf("server saw protocol %q, want HTTP/2.0", b)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+// jsonLevel is a zerolog.Level that's encoded as JSON by its name (e.g.,
+// "info"), rather than as the number zerolog uses. It decodes both, so JSON
+// written before levels were named still parses.
+type jsonLevel zerolog.Level
+
+// MarshalJSON satisfies json.Marshaler.
+func (l jsonLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(zerolog.Level(l).String())
+}
+
+// UnmarshalJSON satisfies json.Unmarshaler.
+func (l *jsonLevel) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] != '"' {
+		i, err := strconv.ParseInt(string(bytes.TrimSpace(b)), 10, 8)
+		if err != nil {
+			return fmt.Errorf("invalid log level %s", b)
+		}
+
+		*l = jsonLevel(i)
+
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	lvl, err := parseLogLevel(s)
+	if err != nil {
+		return err
+	}
+
+	*l = jsonLevel(lvl)
+
+	return nil
+}
+
+// UnmarshalJSON satisfies json.Unmarshaler. The environment is parsed like
+// ENV, except that unknown environments are an error. null leaves it
+// unchanged.
+func (e *Environment) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	env, err := parseEnvironment(s)
+	if err != nil {
+		return err
+	}
+
+	*e = env
+
+	return nil
+}
+
+// plainC is C without its JSON methods, so they can encode the other fields
+// with the default encoding without recursing.
+type plainC C
+
+// MarshalJSON satisfies json.Marshaler. It renders the log levels by name,
+// but otherwise uses the default encoding.
+func (c C) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		plainC
+		LogLevel      jsonLevel `json:"log_level"`
+		AuditLogLevel jsonLevel `json:"audit_log_level"`
+	}{
+		plainC:        plainC(c),
+		LogLevel:      jsonLevel(c.LogLevel),
+		AuditLogLevel: jsonLevel(c.AuditLogLevel),
+	})
+}
+
+// UnmarshalJSON satisfies json.Unmarshaler. Log levels may be given by name
+// or number, and fields missing from the JSON are left unchanged.
+func (c *C) UnmarshalJSON(b []byte) error {
+	v := struct {
+		*plainC
+		LogLevel      *jsonLevel `json:"log_level"`
+		AuditLogLevel *jsonLevel `json:"audit_log_level"`
+	}{
+		plainC:        (*plainC)(c),
+		LogLevel:      (*jsonLevel)(&c.LogLevel),
+		AuditLogLevel: (*jsonLevel)(&c.AuditLogLevel),
+	}
+
+	return json.Unmarshal(b, &v)
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rs/zerolog"
+)
+
+func TestC_MarshalJSON(t *testing.T) {
+	c := C{
+		Env:           Staging,
+		Port:          1234,
+		LogLevel:      zerolog.WarnLevel,
+		AuditLogLevel: zerolog.ErrorLevel,
+		Slack:         S{TeamID: "T123", AckTimeout: time.Second},
+	}
+
+	b, err := json.Marshal(c)
+	testErrCheck(t, "json.Marshal()", "", err)
+
+	for _, want := range []string{`"log_level":"warn"`, `"audit_log_level":"error"`, `"env":"staging"`, `"team_id":"T123"`} {
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("json.Marshal() = %s, want it to contain %s", b, want)
+		}
+	}
+
+	var got C
+	err = json.Unmarshal(b, &got)
+	testErrCheck(t, "json.Unmarshal()", "", err)
+
+	cmpDiff(t, "json.Unmarshal()", cmp.Diff(c, got))
+}
+
+func TestC_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		want   zerolog.Level
+		errStr string
+	}{
+		{name: "name", json: `{"log_level": "warn"}`, want: zerolog.WarnLevel},
+		{name: "debug", json: `{"log_level": "debug"}`, want: zerolog.DebugLevel},
+		{name: "number", json: `{"log_level": 3}`, want: zerolog.ErrorLevel},
+		{name: "missing", json: `{"port": 1}`, want: zerolog.InfoLevel},
+		{name: "bad_name", json: `{"log_level": "loud"}`, errStr: "loud"},
+		{name: "bad_number", json: `{"log_level": 1.5}`, errStr: "invalid log level 1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := C{LogLevel: zerolog.InfoLevel}
+
+			err := json.Unmarshal([]byte(tt.json), &c)
+			if cont := testErrCheck(t, "json.Unmarshal()", tt.errStr, err); !cont {
+				return
+			}
+
+			if c.LogLevel != tt.want {
+				t.Fatalf("LogLevel = %s, want %s", c.LogLevel, tt.want)
+			}
+		})
+	}
+}
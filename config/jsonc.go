@@ -0,0 +1,179 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// LoadJSONC loads the configuration from JSON with comments, using the json
+// tags on C as the keys. Both // and /* */ comments are allowed, as are
+// trailing commas in objects and arrays. Fields missing from the JSON keep the
+// defaults LoadEnv would use with only ENV set to the JSON's "env".
+func LoadJSONC(r io.Reader) (C, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return C{}, fmt.Errorf("failed to read JSONC config: %w", err)
+	}
+
+	b, err = stripJSONC(b)
+	if err != nil {
+		return C{}, err
+	}
+
+	c, err := load(func(string) string { return "" })
+	if err != nil {
+		return C{}, err
+	}
+
+	if err := json.Unmarshal(b, &c); err != nil {
+		if line, ok := jsonErrorLine(b, err); ok {
+			return C{}, fmt.Errorf("failed to parse JSONC config line %d: %w", line, err)
+		}
+
+		return C{}, fmt.Errorf("failed to parse JSONC config: %w", err)
+	}
+
+	applyEnvDefaults(&c, jsonSetPaths(b))
+
+	if err := c.Validate(); err != nil {
+		return C{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return c, nil
+}
+
+// jsonSetPaths returns a func reporting whether the JSON config b sets the
+// field at a Set path (e.g., "redis.db"), for applyEnvDefaults. Keys are
+// matched case-insensitively, like json.Unmarshal does, and null values
+// count as not set, as they leave the field unchanged.
+func jsonSetPaths(b []byte) func(path string) bool {
+	var m map[string]interface{}
+
+	// b has already been decoded successfully
+	_ = json.Unmarshal(b, &m)
+
+	return func(path string) bool {
+		var v interface{} = m
+
+		for _, k := range strings.Split(path, ".") {
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return false
+			}
+
+			v = nil
+
+			for name, ov := range obj {
+				if strings.EqualFold(name, k) {
+					v = ov
+					break
+				}
+			}
+
+			if v == nil {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// stripJSONC returns a copy of b with comments and trailing commas replaced by
+// spaces. Newlines inside comments are kept, so offsets (and line numbers) in
+// any later decoding error still match the original input.
+func stripJSONC(b []byte) ([]byte, error) {
+	out := make([]byte, len(b))
+	copy(out, b)
+
+	blank := func(from, to int) {
+		for i := from; i < to; i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(out); i++ {
+		switch out[i] {
+		case '"':
+			// skip to the closing quote, honoring escapes
+			for i++; i < len(out) && out[i] != '"'; i++ {
+				if out[i] == '\\' {
+					i++
+				}
+			}
+
+		case '/':
+			if i+1 >= len(out) {
+				continue
+			}
+
+			switch out[i+1] {
+			case '/':
+				end := bytes.IndexByte(out[i:], '\n')
+				if end == -1 {
+					end = len(out) - i
+				}
+
+				blank(i, i+end)
+				i += end
+
+			case '*':
+				end := bytes.Index(out[i+2:], []byte("*/"))
+				if end == -1 {
+					return nil, fmt.Errorf("failed to parse JSONC config line %d: unterminated /* comment", lineAt(out, i))
+				}
+
+				blank(i, i+2+end+2)
+				i += 2 + end + 1
+			}
+
+		case '}', ']':
+			j := i - 1
+			for j >= 0 && isJSONSpace(out[j]) {
+				j--
+			}
+
+			if j >= 0 && out[j] == ',' {
+				out[j] = ' '
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// lineAt returns the 1-based line number of offset in b.
+func lineAt(b []byte, offset int) int {
+	if offset > len(b) {
+		offset = len(b)
+	}
+
+	return bytes.Count(b[:offset], []byte("\n")) + 1
+}
+
+// jsonErrorLine returns the line in b that a json.Unmarshal error refers to,
+// if the error carries an offset.
+func jsonErrorLine(b []byte, err error) (int, bool) {
+	var se *json.SyntaxError
+	if errors.As(err, &se) {
+		return lineAt(b, int(se.Offset)), true
+	}
+
+	var te *json.UnmarshalTypeError
+	if errors.As(err, &te) {
+		return lineAt(b, int(te.Offset)), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rs/zerolog"
+)
+
+func TestLoadJSONC(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  C
+		err   string
+	}{
+		{
+			name: "comments_and_trailing_commas",
+			input: `{
+	// the public port
+	"port": 8080,
+	"health_path": "/_health", /* not the default */
+	"slack": {
+		"app_id": "A12345", // "not a comment"
+		"request_secret": "abc//123",
+		"max_timestamp_skew": 60000000000,
+	},
+	"redis": {"tls_cipher_suites": ["TLS_AES_128_GCM_SHA256",],},
+}`,
+			want: C{
+				LogLevel:                 zerolog.InfoLevel,
+				Env:                      Development,
+				Port:                     8080,
+				MaxRequestBytes:          1 << 20,
+				HealthPath:               "/_health",
+				Timezone:                 "UTC",
+				Locale:                   "en-US",
+				MaxConcurrentEvents:      runtime.NumCPU() * 4,
+				RedisFailMode:            RedisFailClosed,
+				MaxMessageLength:         40000,
+				MaxBlocksPerMessage:      50,
+				RedisPoolMetricsInterval: 15 * time.Second,
+				AuditLogLevel:            zerolog.InfoLevel,
+				MaintenanceMessage:       defaultMaintenanceMessage,
+				Redis:                    R{DB: 3, TLSCipherSuites: []string{"TLS_AES_128_GCM_SHA256"}},
+				Slack: S{
+					Disabled:          true,
+					AppID:             "A12345",
+					RequestSecret:     "abc//123",
+					MaxTimestampSkew:  time.Minute,
+					AckMode:           AckModeImmediate,
+					AckTimeout:        2500 * time.Millisecond,
+					MaxRetryNum:       3,
+					CommandPrefix:     "!",
+					RespondToMentions: true,
+				},
+			},
+		},
+		{
+			name:  "type_error_line",
+			input: "{\n  // comment\n  \"port\": \"8080\"\n}",
+			err:   "failed to parse JSONC config line 3",
+		},
+		{
+			name:  "syntax_error_line",
+			input: "{\n  \"port\": 8080\n  \"health_path\": \"/x\"\n}",
+			err:   "failed to parse JSONC config line 3",
+		},
+		{
+			name:  "unterminated_comment",
+			input: "{\n  /* oops\n}",
+			err:   "failed to parse JSONC config line 2: unterminated /* comment",
+		},
+		{
+			name:  "production_capitalized",
+			input: `{"env": "Production"}`,
+			err:   "one of Slack.RequestSecret or Slack.RequestToken is required in production",
+		},
+		{
+			name:  "unknown_env",
+			input: `{"env": "prod"}`,
+			err:   `unknown environment "prod"`,
+		},
+		{
+			name:  "invalid",
+			input: `{"max_message_length": -1}`,
+			err:   "invalid configuration: MaxMessageLength must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadJSONC(strings.NewReader(tt.input))
+			if cont := testErrCheck(t, "LoadJSONC", tt.err, err); !cont {
+				return
+			}
+
+			cmpDiff(t, "LoadJSONC", cmp.Diff(tt.want, got))
+		})
+	}
+}
+
+func TestLoadJSONC_envDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		db       int
+		disabled bool
+		metrics  bool
+	}{
+		{
+			name:     "development",
+			input:    `{}`,
+			db:       3,
+			disabled: true,
+		},
+		{
+			name:    "production",
+			input:   `{"env": "production", "slack": {"request_secret": "abc"}}`,
+			db:      0,
+			metrics: true,
+		},
+		{
+			name:    "production_capitalized",
+			input:   `{"env": "Production", "slack": {"request_secret": "abc"}}`,
+			db:      0,
+			metrics: true,
+		},
+		{
+			name:    "production_explicit_db",
+			input:   `{"env": "production", "redis": {"db": 3}, "slack": {"request_secret": "abc"}}`,
+			db:      3,
+			metrics: true,
+		},
+		{
+			name:    "production_null_db",
+			input:   `{"env": "production", "redis": {"db": null}, "slack": {"request_secret": "abc"}}`,
+			db:      0,
+			metrics: true,
+		},
+		{
+			name:  "production_metrics_off",
+			input: `{"env": "production", "metrics_enabled": false, "slack": {"request_secret": "abc"}}`,
+			db:    0,
+		},
+		{
+			name:  "development_bot_token",
+			input: `{"slack": {"bot_access_token": "xoxb-123"}}`,
+			db:    3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := LoadJSONC(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("LoadJSONC() error = %v", err)
+			}
+
+			if c.Redis.DB != tt.db {
+				t.Errorf("Redis.DB = %d, want %d", c.Redis.DB, tt.db)
+			}
+
+			if c.Slack.Disabled != tt.disabled {
+				t.Errorf("Slack.Disabled = %t, want %t", c.Slack.Disabled, tt.disabled)
+			}
+
+			if c.MetricsEnabled != tt.metrics {
+				t.Errorf("MetricsEnabled = %t, want %t", c.MetricsEnabled, tt.metrics)
+			}
+		})
+	}
+}
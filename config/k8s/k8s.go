@@ -0,0 +1,344 @@
+// Package k8s loads the gopher configuration from a Kubernetes ConfigMap and
+// Secret, by reading them from the Kubernetes API instead of mounting them in
+// to the pod. The pod's service account needs permission to get and watch
+// both objects.
+//
+// The keys in the ConfigMap and Secret are the same as those used by
+// config.LoadDir (e.g., "slack_bot_access_token").
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gobridge/gopherbot/config"
+)
+
+// serviceAccountDir is where Kubernetes mounts the pod's service account
+// credentials.
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// Client is a minimal Kubernetes API client, supporting only what's needed to
+// read ConfigMaps and Secrets.
+type Client struct {
+	// BaseURL is the Kubernetes API server URL
+	BaseURL string
+
+	// Token is the bearer token used to authenticate with the API server
+	Token string
+
+	// HTTPClient is the HTTP client used for requests
+	HTTPClient *http.Client
+}
+
+// InClusterClient returns a Client configured using the pod's service account,
+// like client-go's rest.InClusterConfig.
+func InClusterClient() (*Client, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if len(host) == 0 || len(port) == 0 {
+		return nil, errors.New("not running in a Kubernetes cluster: KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT must be set")
+	}
+
+	token, err := ioutil.ReadFile(serviceAccountDir + "/token")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account token: %w", err)
+	}
+
+	ca, err := ioutil.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account CA certificate: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("failed to parse service account CA certificate")
+	}
+
+	return &Client{
+		BaseURL: "https://" + net.JoinHostPort(host, port),
+		Token:   strings.TrimSpace(string(token)),
+		HTTPClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: pool},
+			},
+		},
+	}, nil
+}
+
+// object is the subset of a ConfigMap or Secret that we use.
+type object struct {
+	Data map[string]string `json:"data"`
+}
+
+func (c *Client) do(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected HTTP status: %s", resp.Status)
+	}
+
+	return resp, nil
+}
+
+func (c *Client) get(ctx context.Context, namespace, kind, name string) (object, error) {
+	resp, err := c.do(ctx, fmt.Sprintf("/api/v1/namespaces/%s/%s/%s", namespace, kind, name), nil)
+	if err != nil {
+		return object{}, fmt.Errorf("failed to get %s %s/%s: %w", kind, namespace, name, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var o object
+
+	if err := json.NewDecoder(resp.Body).Decode(&o); err != nil {
+		return object{}, fmt.Errorf("failed to decode %s %s/%s: %w", kind, namespace, name, err)
+	}
+
+	return o, nil
+}
+
+// Load fetches the ConfigMap and Secret and loads the configuration from them.
+// Values in the Secret take precedence over those in the ConfigMap, and either
+// name may be empty to skip that object.
+func (c *Client) Load(ctx context.Context, namespace, configMap, secret string) (config.C, error) {
+	values := make(map[string]string)
+
+	if len(configMap) > 0 {
+		cm, err := c.get(ctx, namespace, "configmaps", configMap)
+		if err != nil {
+			return config.C{}, err
+		}
+
+		for k, v := range cm.Data {
+			values[k] = v
+		}
+	}
+
+	if len(secret) > 0 {
+		s, err := c.get(ctx, namespace, "secrets", secret)
+		if err != nil {
+			return config.C{}, err
+		}
+
+		for k, v := range s.Data {
+			b, err := decodeSecretValue(v)
+			if err != nil {
+				return config.C{}, fmt.Errorf("failed to decode secret key %s: %w", k, err)
+			}
+
+			values[k] = b
+		}
+	}
+
+	return config.LoadMap(values)
+}
+
+// decodeSecretValue decodes a value from a Secret's data, which the API
+// returns base64-encoded. Trailing newlines are trimmed, as files added with
+// kubectl create secret --from-file usually end with one.
+func decodeSecretValue(v string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// Load loads the configuration from the ConfigMap and Secret using the
+// in-cluster client. See Client.Load for details.
+func Load(ctx context.Context, namespace, configMap, secret string) (config.C, error) {
+	c, err := InClusterClient()
+	if err != nil {
+		return config.C{}, err
+	}
+
+	return c.Load(ctx, namespace, configMap, secret)
+}
+
+// SecretSource is a config.SecretSource that resolves secrets from a Secret,
+// using the same keys as Client.Load.
+type SecretSource struct {
+	// Client is the client used to read the Secret
+	Client *Client
+
+	// Namespace is the namespace of the Secret
+	Namespace string
+
+	// Secret is the name of the Secret
+	Secret string
+}
+
+var _ config.BatchSecretSource = SecretSource{}
+
+// Resolve satisfies config.SecretSource. The key is converted from the
+// environment variable name the same way as config.LoadDir, so
+// GOPHER_SLACK_BOT_ACCESS_TOKEN is read from slack_bot_access_token.
+func (s SecretSource) Resolve(ctx context.Context, key string) (string, bool, error) {
+	values, err := s.ResolveAll(ctx, []string{key})
+	if err != nil {
+		return "", false, err
+	}
+
+	v, ok := values[key]
+	return v, ok, nil
+}
+
+// ResolveAll satisfies config.BatchSecretSource, fetching the Secret once for
+// all of keys. Keys are converted the same way as for Resolve.
+func (s SecretSource) ResolveAll(ctx context.Context, keys []string) (map[string]string, error) {
+	o, err := s.Client.get(ctx, s.Namespace, "secrets", s.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(keys))
+
+	for _, key := range keys {
+		v, ok := o.Data[strings.ToLower(strings.TrimPrefix(key, "GOPHER_"))]
+		if !ok {
+			continue
+		}
+
+		b, err := decodeSecretValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode secret key %s: %w", key, err)
+		}
+
+		values[key] = b
+	}
+
+	return values, nil
+}
+
+// Update is sent by Watch each time the configuration is reloaded.
+type Update struct {
+	// Config is the reloaded configuration, if Err is nil
+	Config config.C
+
+	// Err is the error encountered reloading the configuration
+	Err error
+}
+
+// Watch watches the ConfigMap and Secret, and sends an Update on the returned
+// channel each time either of them changes (including the initial state of
+// each when the watch starts). Watches are re-established if the API server
+// closes them, and the channel is closed once ctx is canceled.
+func (c *Client) Watch(ctx context.Context, namespace, configMap, secret string) <-chan Update {
+	updates := make(chan Update)
+	changed := make(chan struct{}, 1)
+
+	watch := func(kind, name string) {
+		if len(name) == 0 {
+			return
+		}
+
+		for ctx.Err() == nil {
+			_ = c.watch(ctx, namespace, kind, name, changed)
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+
+	go watch("configmaps", configMap)
+	go watch("secrets", secret)
+
+	go func() {
+		defer close(updates)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+			}
+
+			cfg, err := c.Load(ctx, namespace, configMap, secret)
+
+			select {
+			case <-ctx.Done():
+				return
+			case updates <- Update{Config: cfg, Err: err}:
+			}
+		}
+	}()
+
+	return updates
+}
+
+// watch streams watch events for the object, signaling changed for each one,
+// until the stream ends or ctx is canceled.
+func (c *Client) watch(ctx context.Context, namespace, kind, name string, changed chan<- struct{}) error {
+	q := url.Values{
+		"watch":         {"true"},
+		"fieldSelector": {"metadata.name=" + name},
+	}
+
+	resp, err := c.do(ctx, fmt.Sprintf("/api/v1/namespaces/%s/%s", namespace, kind), q)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	r := bufio.NewReader(resp.Body)
+
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			var event struct {
+				Type string `json:"type"`
+			}
+
+			if json.Unmarshal(line, &event) == nil && event.Type != "ERROR" && event.Type != "BOOKMARK" {
+				select {
+				case changed <- struct{}{}:
+				default: // a reload is already pending
+				}
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
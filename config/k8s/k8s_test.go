@@ -0,0 +1,229 @@
+package k8s
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeAPI struct {
+	mu         sync.Mutex
+	configMap  map[string]string
+	secret     map[string]string
+	secretGets int
+	watchEvent chan struct{}
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if r.URL.Query().Get("watch") == "true" {
+		if !strings.HasSuffix(r.URL.Path, "/configmaps") {
+			<-r.Context().Done()
+			return
+		}
+
+		w.(http.Flusher).Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-f.watchEvent:
+				fmt.Fprintln(w, `{"type":"MODIFIED","object":{}}`)
+				w.(http.Flusher).Flush()
+			}
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var data []string
+
+	switch r.URL.Path {
+	case "/api/v1/namespaces/gopher/configmaps/gopher-config":
+		for k, v := range f.configMap {
+			data = append(data, fmt.Sprintf("%q:%q", k, v))
+		}
+	case "/api/v1/namespaces/gopher/secrets/gopher-secret":
+		f.secretGets++
+
+		for k, v := range f.secret {
+			data = append(data, fmt.Sprintf("%q:%q", k, base64.StdEncoding.EncodeToString([]byte(v))))
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	fmt.Fprintf(w, `{"data":{%s}}`, strings.Join(data, ","))
+}
+
+func newTestClient(t *testing.T) (*Client, *fakeAPI, func()) {
+	t.Helper()
+
+	f := &fakeAPI{
+		configMap: map[string]string{
+			"env":                    "testing",
+			"slack_team_id":          "T123",
+			"slack_bot_access_token": "from-configmap",
+		},
+		secret: map[string]string{
+			"slack_bot_access_token": "xoxb-123",
+			"slack_request_secret":   "abc",
+			"slack_client_secret":    "client-secret\n",
+		},
+		watchEvent: make(chan struct{}),
+	}
+
+	srv := httptest.NewServer(f)
+
+	c := &Client{
+		BaseURL:    srv.URL,
+		Token:      "test-token",
+		HTTPClient: srv.Client(),
+	}
+
+	return c, f, srv.Close
+}
+
+func TestClient_Load(t *testing.T) {
+	c, _, done := newTestClient(t)
+	defer done()
+
+	cfg, err := c.Load(context.Background(), "gopher", "gopher-config", "gopher-secret")
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	if cfg.Slack.TeamID != "T123" {
+		t.Errorf("Slack.TeamID = %q, want T123", cfg.Slack.TeamID)
+	}
+
+	if cfg.Slack.BotAccessToken != "xoxb-123" {
+		t.Errorf("Slack.BotAccessToken = %q, want the Secret's value", cfg.Slack.BotAccessToken)
+	}
+
+	if cfg.Slack.RequestSecret != "abc" {
+		t.Errorf("Slack.RequestSecret = %q, want abc", cfg.Slack.RequestSecret)
+	}
+
+	if cfg.Slack.ClientSecret != "client-secret" {
+		t.Errorf("Slack.ClientSecret = %q, want the trailing newline trimmed", cfg.Slack.ClientSecret)
+	}
+
+	_, err = c.Load(context.Background(), "gopher", "missing", "")
+	if err == nil || !strings.Contains(err.Error(), "failed to get configmaps gopher/missing: unexpected HTTP status: 404 Not Found") {
+		t.Fatalf("Load() error = %v, want not found error", err)
+	}
+}
+
+func TestSecretSource_Resolve(t *testing.T) {
+	c, _, done := newTestClient(t)
+	defer done()
+
+	src := SecretSource{Client: c, Namespace: "gopher", Secret: "gopher-secret"}
+
+	v, ok, err := src.Resolve(context.Background(), "GOPHER_SLACK_BOT_ACCESS_TOKEN")
+	if err != nil {
+		t.Fatalf("Resolve() unexpected error: %v", err)
+	}
+
+	if !ok || v != "xoxb-123" {
+		t.Fatalf("Resolve() = %q, %t, want xoxb-123, true", v, ok)
+	}
+
+	if _, ok, _ := src.Resolve(context.Background(), "GOPHER_DEBUG_TOKEN"); ok {
+		t.Fatal("Resolve() found a key not in the Secret")
+	}
+
+	src.Secret = "missing"
+
+	if _, _, err := src.Resolve(context.Background(), "GOPHER_DEBUG_TOKEN"); err == nil {
+		t.Fatal("Resolve() expected an error for a missing Secret")
+	}
+}
+
+func TestSecretSource_ResolveAll(t *testing.T) {
+	c, f, done := newTestClient(t)
+	defer done()
+
+	src := SecretSource{Client: c, Namespace: "gopher", Secret: "gopher-secret"}
+
+	keys := []string{"GOPHER_SLACK_BOT_ACCESS_TOKEN", "GOPHER_SLACK_CLIENT_SECRET", "GOPHER_DEBUG_TOKEN"}
+
+	got, err := src.ResolveAll(context.Background(), keys)
+	if err != nil {
+		t.Fatalf("ResolveAll() unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"GOPHER_SLACK_BOT_ACCESS_TOKEN": "xoxb-123",
+		"GOPHER_SLACK_CLIENT_SECRET":    "client-secret",
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("ResolveAll() = %q, want %q", got, want)
+	}
+
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("ResolveAll()[%s] = %q, want %q", k, got[k], v)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.secretGets != 1 {
+		t.Errorf("Secret fetched %d times, want 1", f.secretGets)
+	}
+}
+
+func TestClient_Watch(t *testing.T) {
+	c, f, done := newTestClient(t)
+	defer done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := c.Watch(ctx, "gopher", "gopher-config", "gopher-secret")
+
+	f.mu.Lock()
+	f.configMap["slack_team_id"] = "T456"
+	f.mu.Unlock()
+
+	select {
+	case f.watchEvent <- struct{}{}:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the watch to start")
+	}
+
+	select {
+	case u := <-updates:
+		if u.Err != nil {
+			t.Fatalf("Update.Err = %v", u.Err)
+		}
+
+		if u.Config.Slack.TeamID != "T456" {
+			t.Fatalf("Slack.TeamID = %q, want T456", u.Config.Slack.TeamID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for update")
+	}
+
+	cancel()
+
+	for range updates { // wait for close
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import "time"
+
+const (
+	defaultLockTTL           = 30 * time.Second
+	defaultLockRetryInterval = time.Second
+)
+
+// LockConfig are the settings a Redis lock helper is built with, with the
+// defaults from C.Lock already applied.
+type LockConfig struct {
+	// TTL is how long a lock is held before it expires, unless refreshed
+	TTL time.Duration
+
+	// RetryInterval is how long to wait between attempts to take a lock
+	RetryInterval time.Duration
+
+	// Owner identifies the process holding a lock, and is the dyno ID when
+	// running on Heroku
+	Owner string
+}
+
+// LockConfig returns the settings to build a Redis lock helper with, so that
+// every singleton task uses the same ones.
+func (c C) LockConfig() LockConfig {
+	lc := LockConfig{
+		TTL:           c.Lock.DefaultTTL,
+		RetryInterval: c.Lock.RetryInterval,
+		Owner:         c.Heroku.DynoID,
+	}
+
+	if lc.TTL <= 0 {
+		lc.TTL = defaultLockTTL
+	}
+
+	if lc.RetryInterval <= 0 {
+		lc.RetryInterval = defaultLockRetryInterval
+	}
+
+	return lc
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestC_LockConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		c    C
+		want LockConfig
+	}{
+		{
+			name: "defaults",
+			want: LockConfig{TTL: 30 * time.Second, RetryInterval: time.Second},
+		},
+		{
+			name: "configured",
+			c: C{
+				Heroku: H{DynoID: "def890"},
+				Lock:   Lock{DefaultTTL: time.Minute, RetryInterval: 5 * time.Second},
+			},
+			want: LockConfig{TTL: time.Minute, RetryInterval: 5 * time.Second, Owner: "def890"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.LockConfig(); got != tt.want {
+				t.Fatalf("LockConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Version is the version of the running build, meant to be injected at build
+// time (e.g., -ldflags "-X github.com/gobridge/gopherbot/config.Version=v1.2.3").
+// If it's empty, LogStartupBanner reports the short commit from the Heroku
+// metadata instead.
+var Version string
+
+// defaultServiceName is the service name LogStartupBanner reports when not
+// running on Heroku.
+const defaultServiceName = "gopherbot"
+
+// fieldName returns the name used for the struct field when serialized,
+// based on its json tag.
+func fieldName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if len(name) == 0 {
+		return f.Name
+	}
+
+	return name
+}
+
+// logDict builds a zerolog dictionary of the struct v. Secret fields are
+// included only as a boolean of whether they're set, using the field name with
+// a _set suffix, so that their values are never logged.
+func logDict(v reflect.Value) *zerolog.Event {
+	d := zerolog.Dict()
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+		name := fieldName(f)
+
+		switch {
+		case isIgnored(f):
+			continue
+
+		case isSecret(f):
+			d.Bool(name+"_set", !fv.IsZero())
+
+		case fv.Kind() == reflect.Struct:
+			d.Dict(name, logDict(fv))
+
+		default:
+			if s, ok := fv.Interface().(fmt.Stringer); ok {
+				d.Str(name, s.String())
+				continue
+			}
+
+			d.Interface(name, fv.Interface())
+		}
+	}
+
+	return d
+}
+
+// LoadEnvLogged is LoadEnv, but it also emits a single structured log event
+// to logger recording the configuration that was loaded. Every non-secret
+// field is included, while secrets are only logged as whether they were set.
+func LoadEnvLogged(logger zerolog.Logger) (C, error) {
+	c, err := LoadEnv()
+	if err != nil {
+		return C{}, err
+	}
+
+	logger.Info().
+		Dict("config", logDict(reflect.ValueOf(c))).
+		Msg("configuration loaded")
+
+	return c, nil
+}
+
+// LogStartupBanner emits a single structured log event to logger marking that
+// the service started, with its name, version, environment, and which
+// subsystems are enabled. Unlike LoadEnvLogged it includes no configuration
+// values, so it's safe to log in every environment and easy to grep for.
+func (c C) LogStartupBanner(logger zerolog.Logger) {
+	service := c.Heroku.AppName
+	if len(service) == 0 {
+		service = defaultServiceName
+	}
+
+	version := Version
+	if len(version) == 0 {
+		version = c.Heroku.ShortCommit()
+	}
+
+	logger.Info().
+		Str("service", service).
+		Str("version", version).
+		Str("commit", c.Heroku.ShortCommit()).
+		Str("env", string(c.Env)).
+		Dict("subsystems", zerolog.Dict().
+			Bool("redis", len(c.Redis.Addr) > 0).
+			Bool("metrics", c.MetricsEnabled).
+			Bool("tracing", c.TracingEnabled).
+			Bool("socket_mode", c.Slack.Transport() == TransportSocket),
+		).
+		Msg("gopherbot starting")
+}
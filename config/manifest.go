@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// slackManifest is the subset of a Slack app manifest that we check the
+// configuration against.
+type slackManifest struct {
+	OAuthConfig struct {
+		Scopes struct {
+			Bot []string `yaml:"bot"`
+		} `yaml:"scopes"`
+	} `yaml:"oauth_config"`
+
+	Settings struct {
+		SocketModeEnabled bool `yaml:"socket_mode_enabled"`
+	} `yaml:"settings"`
+}
+
+// ManifestMismatchError is returned by ValidateAgainstManifest, describing each
+// way the configuration doesn't match the app manifest.
+type ManifestMismatchError struct {
+	Mismatches []string
+}
+
+func (e *ManifestMismatchError) Error() string {
+	return "configuration does not match the Slack app manifest: " + strings.Join(e.Mismatches, "; ")
+}
+
+// ValidateAgainstManifest checks the configuration against the Slack app
+// manifest, which may be YAML or JSON, to catch drift between the two at boot.
+// Each of Scopes must be one of the manifest's requested bot scopes, and the
+// Transport must match whether the manifest enables Socket Mode.
+//
+// If they don't match, the error is a *ManifestMismatchError.
+func (s S) ValidateAgainstManifest(manifest []byte) error {
+	var m slackManifest
+
+	// JSON is valid YAML, so this handles both
+	if err := yaml.Unmarshal(manifest, &m); err != nil {
+		return fmt.Errorf("failed to parse Slack app manifest: %w", err)
+	}
+
+	requested := make(map[string]struct{}, len(m.OAuthConfig.Scopes.Bot))
+	for _, scope := range m.OAuthConfig.Scopes.Bot {
+		requested[scope] = struct{}{}
+	}
+
+	var mismatches []string
+
+	for _, scope := range s.Scopes {
+		if _, ok := requested[scope]; !ok {
+			mismatches = append(mismatches, fmt.Sprintf("scope %s is not requested by the manifest", scope))
+		}
+	}
+
+	socket := s.Transport() == TransportSocket
+
+	if socket != m.Settings.SocketModeEnabled {
+		mismatches = append(mismatches, fmt.Sprintf("transport is %s, but the manifest has socket_mode_enabled: %t", s.Transport(), m.Settings.SocketModeEnabled))
+	}
+
+	if len(mismatches) > 0 {
+		return &ManifestMismatchError{Mismatches: mismatches}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testManifestYAML = `display_information:
+  name: Gopherbot
+oauth_config:
+  scopes:
+    bot:
+      - chat:write
+      - channels:read
+      - users:read
+settings:
+  event_subscriptions:
+    bot_events:
+      - message.channels
+  socket_mode_enabled: false
+`
+
+const testManifestJSON = `{
+  "oauth_config": {"scopes": {"bot": ["chat:write", "channels:read"]}},
+  "settings": {"socket_mode_enabled": true}
+}`
+
+func TestS_ValidateAgainstManifest(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          S
+		manifest   string
+		mismatches []string
+		err        string
+	}{
+		{
+			name:     "yaml_match",
+			s:        S{Scopes: []string{"chat:write", "users:read"}},
+			manifest: testManifestYAML,
+		},
+		{
+			name:     "json_match",
+			s:        S{Scopes: []string{"chat:write"}, AppLevelToken: "xapp-123"},
+			manifest: testManifestJSON,
+		},
+		{
+			name:     "mismatches",
+			s:        S{Scopes: []string{"chat:write", "admin", "users:read"}, AppLevelToken: "xapp-123"},
+			manifest: testManifestYAML,
+			mismatches: []string{
+				"scope admin is not requested by the manifest",
+				"transport is socket, but the manifest has socket_mode_enabled: false",
+			},
+		},
+		{
+			name:     "events_with_socket_manifest",
+			s:        S{},
+			manifest: testManifestJSON,
+			mismatches: []string{
+				"transport is events, but the manifest has socket_mode_enabled: true",
+			},
+		},
+		{
+			name:     "invalid",
+			manifest: "oauth_config: [",
+			err:      "failed to parse Slack app manifest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.s.ValidateAgainstManifest([]byte(tt.manifest))
+
+			if len(tt.mismatches) > 0 {
+				var me *ManifestMismatchError
+				if !errors.As(err, &me) {
+					t.Fatalf("ValidateAgainstManifest() error = %v, want a *ManifestMismatchError", err)
+				}
+
+				cmpDiff(t, "Mismatches", cmp.Diff(tt.mismatches, me.Mismatches))
+
+				return
+			}
+
+			testErrCheck(t, "ValidateAgainstManifest()", tt.err, err)
+		})
+	}
+}
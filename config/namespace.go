@@ -0,0 +1,75 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// namespaceRE matches the namespace names LoadEnvNamespaces accepts.
+var namespaceRE = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// namespaceKey returns the name of the environment variable overriding key in
+// the namespace name. The GOPHER_ prefix is replaced, so in the "dev"
+// namespace GOPHER_SLACK_TEAM_ID is overridden by GOPHER_DEV_SLACK_TEAM_ID and
+// PORT by GOPHER_DEV_PORT.
+func namespaceKey(name, key string) string {
+	return "GOPHER_" + strings.ToUpper(name) + "_" + strings.TrimPrefix(key, "GOPHER_")
+}
+
+// LoadEnvNamespaces loads a configuration for each of the named namespaces,
+// so that one process can serve several environments (e.g., dev and staging).
+// Each is loaded like LoadEnv, except that every environment variable can be
+// overridden for the namespace by its GOPHER_<NAME>_* counterpart (see
+// namespaceKey). A namespace-specific variable always takes precedence over
+// the global one, even if it's set to an empty value, and globals are used
+// for anything a namespace doesn't set. The result is keyed by name, as
+// given.
+//
+// Names must be letters and digits, so the variables aren't ambiguous.
+func LoadEnvNamespaces(names ...string) (map[string]C, error) {
+	if len(names) == 0 {
+		return nil, errors.New("at least one namespace is required")
+	}
+
+	cs := make(map[string]C, len(names))
+
+	for _, name := range names {
+		if !namespaceRE.MatchString(name) {
+			return nil, fmt.Errorf("invalid namespace %q, must be letters and digits", name)
+		}
+
+		if _, ok := cs[name]; ok {
+			return nil, fmt.Errorf("duplicate namespace %q", name)
+		}
+
+		c, err := load(func(key string) string {
+			if v, ok := os.LookupEnv(namespaceKey(name, key)); ok {
+				return v
+			}
+
+			return os.Getenv(key)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load namespace %s: %w", name, err)
+		}
+
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid configuration for namespace %s: %w", name, err)
+		}
+
+		cs[name] = c
+	}
+
+	for _, k := range secretEnvKeys {
+		_ = os.Unsetenv(k) // paranoia
+
+		for _, name := range names {
+			_ = os.Unsetenv(namespaceKey(name, k)) // the overrides are secrets too
+		}
+	}
+
+	return cs, nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLoadEnvNamespaces(t *testing.T) {
+	env := map[string]string{
+		"ENV":                                "development",
+		"GOPHER_STAGING_ENV":                 "staging",
+		"GOPHER_SLACK_TEAM_ID":               "T1",
+		"GOPHER_DEV_SLACK_TEAM_ID":           "T2",
+		"GOPHER_HEALTH_PATH":                 "/_health",
+		"GOPHER_STAGING_HEALTH_PATH":         "",
+		"GOPHER_DEBUG_TOKEN":                 "debug123",
+		"GOPHER_STAGING_DEBUG_TOKEN":         "staging123",
+		"GOPHER_STAGING_SLACK_REQUEST_TOKEN": "xyz",
+	}
+
+	set := func() {
+		for k, v := range env {
+			_ = os.Setenv(k, v)
+		}
+	}
+
+	defer func() {
+		for k := range env {
+			_ = os.Unsetenv(k)
+		}
+	}()
+
+	t.Run("namespaces", func(t *testing.T) {
+		set()
+
+		cs, err := LoadEnvNamespaces("dev", "staging")
+		testErrCheck(t, "LoadEnvNamespaces()", "", err)
+
+		dev, staging := cs["dev"], cs["staging"]
+
+		if dev.Env != Development || dev.Slack.TeamID != "T2" || dev.HealthPath != "/_health" || dev.DebugToken != "debug123" {
+			t.Fatalf("dev = Env %q, TeamID %q, HealthPath %q, DebugToken %q", dev.Env, dev.Slack.TeamID, dev.HealthPath, dev.DebugToken)
+		}
+
+		// an empty namespace value takes precedence, so the default is used
+		if staging.Env != Staging || staging.Slack.TeamID != "T1" || staging.HealthPath != "/healthz" || staging.DebugToken != "staging123" {
+			t.Fatalf("staging = Env %q, TeamID %q, HealthPath %q, DebugToken %q", staging.Env, staging.Slack.TeamID, staging.HealthPath, staging.DebugToken)
+		}
+
+		for _, k := range []string{"GOPHER_DEBUG_TOKEN", "GOPHER_STAGING_DEBUG_TOKEN"} {
+			if _, ok := os.LookupEnv(k); ok {
+				t.Errorf("%s is still set", k)
+			}
+		}
+	})
+
+	tests := []struct {
+		name  string
+		names []string
+		err   string
+	}{
+		{name: "none", err: "at least one namespace is required"},
+		{name: "invalid_name", names: []string{"dev_1"}, err: `invalid namespace "dev_1", must be letters and digits`},
+		{name: "duplicate", names: []string{"dev", "dev"}, err: `duplicate namespace "dev"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set()
+
+			_, err := LoadEnvNamespaces(tt.names...)
+			testErrCheck(t, "LoadEnvNamespaces()", tt.err, err)
+		})
+	}
+
+	t.Run("bad_value", func(t *testing.T) {
+		set()
+		_ = os.Setenv("GOPHER_DEV_PORT", "abc")
+		defer func() { _ = os.Unsetenv("GOPHER_DEV_PORT") }()
+
+		_, err := LoadEnvNamespaces("dev")
+		testErrCheck(t, "LoadEnvNamespaces()", "failed to load namespace dev: failed to parse PORT", err)
+	})
+}
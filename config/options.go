@@ -0,0 +1,180 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-redis/redis"
+)
+
+// LoadOptions are optional behaviors for LoadEnvWith, beyond what LoadEnv
+// does by default.
+type LoadOptions struct {
+	// VerifyRedis is whether to connect to Redis and PING it once the
+	// configuration is loaded, so that an unreachable or misconfigured Redis
+	// fails at boot rather than on first use. The connection attempt is
+	// bounded by DefaultRedis's dial timeout.
+	VerifyRedis bool
+
+	// SecretSource, if set, is where secrets are resolved from before
+	// falling back to the environment. See LoadWithSecretSource.
+	SecretSource SecretSource
+
+	// RequireSecretSource is whether loading fails in Production if any secret
+	// was read from the plain environment, rather than resolved from the
+	// SecretSource. This includes the Redis URLs, as they embed the Redis
+	// password.
+	// Other environments are unaffected.
+	RequireSecretSource bool
+
+	// Strict is whether loading fails if any of the environment variables a
+	// deployment in the environment is expected to set (see
+	// strictExpectedEnvKeys) is unset, rather than silently using its default.
+	// Secrets count as set if they're resolved from the SecretSource.
+	Strict bool
+}
+
+// strictExpectedEnvKeys are the environment variables LoadOptions.Strict
+// requires in each environment. Local development only needs enough to talk
+// to Slack and Redis, while deployed environments must also pin down what
+// Heroku or the defaults would otherwise decide for them. REDIS_URL is also
+// satisfied by REDIS_TLS_URL.
+var strictExpectedEnvKeys = map[Environment][]string{
+	Development: {"ENV", "REDIS_URL", "GOPHER_SLACK_BOT_ACCESS_TOKEN"},
+	Testing:     {"ENV", "REDIS_URL", "GOPHER_SLACK_BOT_ACCESS_TOKEN"},
+	Staging: {
+		"ENV", "PORT", "REDIS_URL", "GOPHER_LOG_LEVEL",
+		"GOPHER_SLACK_BOT_ACCESS_TOKEN", "GOPHER_SLACK_REQUEST_SECRET", "GOPHER_SLACK_TEAM_ID",
+	},
+	Production: {
+		"ENV", "PORT", "REDIS_URL", "GOPHER_LOG_LEVEL",
+		"GOPHER_SLACK_BOT_ACCESS_TOKEN", "GOPHER_SLACK_REQUEST_SECRET", "GOPHER_SLACK_TEAM_ID",
+	},
+}
+
+// LoadEnvWith is LoadEnv, with the additional behaviors enabled in opts.
+func LoadEnvWith(ctx context.Context, opts LoadOptions) (C, error) {
+	secrets := make(map[string]string, len(sourcedSecretKeys))
+
+	if bs, ok := opts.SecretSource.(BatchSecretSource); ok {
+		var err error
+
+		if secrets, err = bs.ResolveAll(ctx, sourcedSecretKeys); err != nil {
+			return C{}, fmt.Errorf("failed to resolve secrets: %w", err)
+		}
+	} else if opts.SecretSource != nil {
+		for _, k := range sourcedSecretKeys {
+			v, ok, err := opts.SecretSource.Resolve(ctx, k)
+			if err != nil {
+				return C{}, fmt.Errorf("failed to resolve secret %s: %w", k, err)
+			}
+
+			if ok {
+				secrets[k] = v
+			}
+		}
+	}
+
+	// track which secrets came from the plain environment, and which
+	// variables were set at all
+	fromEnv := make(map[string]struct{})
+	set := make(map[string]struct{})
+
+	c, err := load(func(key string) string {
+		if v, ok := secrets[key]; ok {
+			set[key] = struct{}{}
+			return v
+		}
+
+		v := os.Getenv(key)
+
+		if len(v) > 0 {
+			set[key] = struct{}{}
+		}
+
+		if len(v) > 0 && isSecretEnvKey(key) {
+			fromEnv[key] = struct{}{}
+		}
+
+		return v
+	})
+	if err != nil {
+		return C{}, err
+	}
+
+	for _, k := range secretEnvKeys {
+		_ = os.Unsetenv(k) // paranoia
+	}
+
+	if opts.RequireSecretSource && c.Env == Production && len(fromEnv) > 0 {
+		keys := make([]string, 0, len(fromEnv))
+
+		for k := range fromEnv {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		return C{}, fmt.Errorf("secrets must be resolved from the secret source in %s, but were read from the environment: %s", c.Env, strings.Join(keys, ", "))
+	}
+
+	if opts.Strict {
+		var unset []string
+
+		for _, k := range strictExpectedEnvKeys[c.Env] {
+			if _, ok := set[k]; ok {
+				continue
+			}
+
+			if _, ok := set["REDIS_TLS_URL"]; ok && k == "REDIS_URL" {
+				continue
+			}
+
+			unset = append(unset, k)
+		}
+
+		if len(unset) > 0 {
+			sort.Strings(unset)
+
+			return C{}, fmt.Errorf("strict mode requires these environment variables to be set in %s: %s", c.Env, strings.Join(unset, ", "))
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return C{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if opts.VerifyRedis {
+		if err := verifyRedis(c); err != nil {
+			return C{}, err
+		}
+	}
+
+	return c, nil
+}
+
+// isSecretEnvKey returns whether the environment variable holds a secret.
+func isSecretEnvKey(key string) bool {
+	for _, k := range sourcedSecretKeys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyRedis connects to Redis using DefaultRedis and sends a PING.
+func verifyRedis(c C) error {
+	rc := redis.NewClient(DefaultRedis(c))
+	defer func() { _ = rc.Close() }()
+
+	if err := rc.Ping().Err(); err != nil {
+		return fmt.Errorf("failed to verify Redis connection to %s: %w", c.Redis.Addr, err)
+	}
+
+	return nil
+}
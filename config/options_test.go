@@ -0,0 +1,174 @@
+package config
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+)
+
+func TestLoadEnvWith_verifyRedis(t *testing.T) {
+	f := newFakeRedis(t)
+	defer f.Close()
+
+	_ = os.Setenv("ENV", "testing")
+	_ = os.Setenv("REDIS_URL", "redis://"+f.Addr())
+	_ = os.Setenv("GOPHER_REDIS_INSECURE", "1")
+
+	defer func() {
+		for _, v := range []string{"ENV", "REDIS_URL", "GOPHER_REDIS_INSECURE"} {
+			_ = os.Unsetenv(v)
+		}
+	}()
+
+	_, err := LoadEnvWith(context.Background(), LoadOptions{VerifyRedis: true})
+	testErrCheck(t, "LoadEnvWith()", "", err)
+
+	// find a port with nothing listening on it
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	testErrCheck(t, "net.Listen()", "", err)
+
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	_ = os.Setenv("REDIS_URL", "redis://"+addr)
+
+	_, err = LoadEnvWith(context.Background(), LoadOptions{})
+	testErrCheck(t, "LoadEnvWith()", "", err)
+
+	_, err = LoadEnvWith(context.Background(), LoadOptions{VerifyRedis: true})
+	testErrCheck(t, "LoadEnvWith()", "failed to verify Redis connection to "+addr, err)
+}
+
+func TestLoadEnvWith_requireSecretSource(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		src  SecretSource
+		err  string
+	}{
+		{
+			name: "production_from_env",
+			env:  "production",
+			err:  "secrets must be resolved from the secret source in production, but were read from the environment: GOPHER_SLACK_BOT_ACCESS_TOKEN, GOPHER_SLACK_REQUEST_SECRET",
+		},
+		{
+			name: "production_partly_from_env",
+			env:  "production",
+			src:  mapSecretSource{"GOPHER_SLACK_REQUEST_SECRET": "abc"},
+			err:  "secrets must be resolved from the secret source in production, but were read from the environment: GOPHER_SLACK_BOT_ACCESS_TOKEN",
+		},
+		{
+			name: "production_from_source",
+			env:  "production",
+			src:  mapSecretSource{"GOPHER_SLACK_REQUEST_SECRET": "abc", "GOPHER_SLACK_BOT_ACCESS_TOKEN": "xoxb-123"},
+		},
+		{
+			name: "development_from_env",
+			env:  "development",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = os.Setenv("ENV", tt.env)
+			_ = os.Setenv("GOPHER_SLACK_REQUEST_SECRET", "abc")
+			_ = os.Setenv("GOPHER_SLACK_BOT_ACCESS_TOKEN", "xoxb-123")
+
+			defer func() {
+				for _, v := range []string{"ENV", "GOPHER_SLACK_REQUEST_SECRET", "GOPHER_SLACK_BOT_ACCESS_TOKEN"} {
+					_ = os.Unsetenv(v)
+				}
+			}()
+
+			_, err := LoadEnvWith(context.Background(), LoadOptions{SecretSource: tt.src, RequireSecretSource: true})
+			testErrCheck(t, "LoadEnvWith()", tt.err, err)
+		})
+	}
+}
+
+func TestLoadEnvWith_strict(t *testing.T) {
+	production := map[string]string{
+		"ENV":                           "production",
+		"PORT":                          "1234",
+		"REDIS_URL":                     "rediss://redis.example.org:6380",
+		"GOPHER_LOG_LEVEL":              "info",
+		"GOPHER_SLACK_BOT_ACCESS_TOKEN": "xoxb-123",
+		"GOPHER_SLACK_REQUEST_SECRET":   "abc",
+		"GOPHER_SLACK_TEAM_ID":          "T123",
+	}
+
+	withTLSURL := map[string]string{"REDIS_TLS_URL": "rediss://redis.example.org:6380"}
+	for k, v := range production {
+		withTLSURL[k] = v
+	}
+
+	tests := []struct {
+		name   string
+		env    map[string]string
+		unset  string
+		strict bool
+		src    SecretSource
+		err    string
+	}{
+		{
+			name:   "production_all_set",
+			env:    production,
+			strict: true,
+		},
+		{
+			name:  "production_lenient",
+			env:   production,
+			unset: "GOPHER_LOG_LEVEL",
+		},
+		{
+			name:   "production_strict",
+			env:    production,
+			unset:  "GOPHER_LOG_LEVEL",
+			strict: true,
+			err:    "strict mode requires these environment variables to be set in production: GOPHER_LOG_LEVEL",
+		},
+		{
+			name:   "production_secret_from_source",
+			env:    production,
+			unset:  "GOPHER_SLACK_REQUEST_SECRET",
+			strict: true,
+			src:    mapSecretSource{"GOPHER_SLACK_REQUEST_SECRET": "abc"},
+		},
+		{
+			name:   "production_redis_tls_url",
+			env:    withTLSURL,
+			unset:  "REDIS_URL",
+			strict: true,
+		},
+		{
+			name:   "development_strict",
+			env:    map[string]string{"GOPHER_LOG_LEVEL": "debug"},
+			strict: true,
+			err:    "strict mode requires these environment variables to be set in development: ENV, GOPHER_SLACK_BOT_ACCESS_TOKEN, REDIS_URL",
+		},
+		{
+			name: "development_lenient",
+			env:  map[string]string{"GOPHER_LOG_LEVEL": "debug"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				if k != tt.unset {
+					_ = os.Setenv(k, v)
+				}
+			}
+
+			defer func() {
+				for k := range tt.env {
+					_ = os.Unsetenv(k)
+				}
+			}()
+
+			_, err := LoadEnvWith(context.Background(), LoadOptions{Strict: tt.strict, SecretSource: tt.src})
+			testErrCheck(t, "LoadEnvWith()", tt.err, err)
+		})
+	}
+}
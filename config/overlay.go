@@ -0,0 +1,89 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/rs/zerolog"
+)
+
+// featureOverlayPrefix is the prefix of the OverlayFromRedis keys that set
+// feature flags, like feature:debugcmd.
+const featureOverlayPrefix = "feature:"
+
+// OverlayFromRedis reads the Redis hash key and applies the operational
+// toggles it recognizes onto c, so they can be flipped without a redeploy:
+//
+//	maintenance     sets MaintenanceMode, parsed like GOPHER_MAINTENANCE
+//	log_level       sets LogLevel, parsed like GOPHER_LOG_LEVEL
+//	feature:<name>  turns the known feature flag name on or off for this
+//	                environment, taking precedence over its env vars
+//
+// Nothing else can be set this way, least of all secrets. Unknown keys are
+// skipped with a warning logged to the zerolog.Ctx(ctx) logger. If any value
+// fails to parse c is left unchanged, and the result isn't validated so
+// callers re-reading the hash periodically should call Validate before using
+// it.
+func (c *C) OverlayFromRedis(ctx context.Context, client *redis.Client, key string) error {
+	values, err := client.WithContext(ctx).HGetAll(key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read config overlay from Redis: %w", err)
+	}
+
+	// sorted so that warnings are logged in a stable order
+	fields := make([]string, 0, len(values))
+	for f := range values {
+		fields = append(fields, f)
+	}
+
+	sort.Strings(fields)
+
+	o := *c
+	features := make(map[string]bool)
+
+	for _, f := range fields {
+		v := values[f]
+
+		switch name := strings.TrimPrefix(f, featureOverlayPrefix); {
+		case f == "maintenance":
+			o.MaintenanceMode, err = parseBool(v)
+
+		case f == "log_level":
+			o.LogLevel, err = parseLogLevel(v)
+
+		case len(name) < len(f) && builtinFeatures[name] != nil:
+			features[name], err = parseBool(v)
+
+		default:
+			zerolog.Ctx(ctx).Warn().
+				Str("redis_key", key).
+				Str("field", f).
+				Msg("ignoring unknown config overlay field")
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to parse config overlay field %s: %w", f, err)
+		}
+	}
+
+	if len(features) > 0 {
+		// copied so that c's map, which may be shared, isn't modified
+		ef := make(map[string]bool, len(o.EnvFeatures)+len(features))
+		for k, v := range o.EnvFeatures {
+			ef[k] = v
+		}
+
+		for k, v := range features {
+			ef[k] = v
+		}
+
+		o.EnvFeatures = ef
+	}
+
+	*c = o
+
+	return nil
+}
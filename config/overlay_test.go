@@ -0,0 +1,144 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-redis/redis"
+	"github.com/google/go-cmp/cmp"
+	"github.com/rs/zerolog"
+)
+
+func TestC_OverlayFromRedis(t *testing.T) {
+	base := C{
+		Env:                Production,
+		LogLevel:           zerolog.InfoLevel,
+		MaintenanceMessage: defaultMaintenanceMessage,
+		Slack:              S{BotAccessToken: "xoxb-123"},
+		EnvFeatures:        map[string]bool{FeatureDebugCommand: false},
+	}
+
+	tests := []struct {
+		name   string
+		hash   map[string]string
+		want   C
+		warn   string
+		errStr string
+	}{
+		{
+			name: "empty",
+			hash: map[string]string{},
+			want: base,
+		},
+		{
+			name: "toggles",
+			hash: map[string]string{
+				"maintenance":       "on",
+				"log_level":         "debug",
+				"feature:debugcmd":  "true",
+				"maintenance_extra": "1",
+			},
+			want: func() C {
+				c := base
+				c.MaintenanceMode = true
+				c.LogLevel = zerolog.DebugLevel
+				c.EnvFeatures = map[string]bool{FeatureDebugCommand: true}
+				return c
+			}(),
+			warn: "maintenance_extra",
+		},
+		{
+			name: "secrets_ignored",
+			hash: map[string]string{"slack.bot_access_token": "xoxb-evil", "bot_access_token": "xoxb-evil"},
+			want: base,
+			warn: "bot_access_token",
+		},
+		{
+			name: "unknown_feature",
+			hash: map[string]string{"feature:nope": "on"},
+			want: base,
+			warn: "feature:nope",
+		},
+		{
+			name:   "bad_bool",
+			hash:   map[string]string{"log_level": "warn", "maintenance": "maybe"},
+			want:   base,
+			errStr: "failed to parse config overlay field maintenance",
+		},
+		{
+			name:   "bad_log_level",
+			hash:   map[string]string{"log_level": "loud"},
+			want:   base,
+			errStr: "failed to parse config overlay field log_level",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeRedis(t)
+			defer f.Close()
+
+			f.SetHash(tt.hash)
+
+			client := redis.NewClient(&redis.Options{Addr: f.Addr()})
+			defer func() { _ = client.Close() }()
+
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf)
+			ctx := logger.WithContext(context.Background())
+
+			c := base
+			c.EnvFeatures = map[string]bool{FeatureDebugCommand: false}
+
+			err := c.OverlayFromRedis(ctx, client, "gopher:config")
+			if cont := testErrCheck(t, "OverlayFromRedis()", tt.errStr, err); !cont {
+				cmpDiff(t, "C after failed overlay", cmp.Diff(tt.want, c))
+				return
+			}
+
+			cmpDiff(t, "C", cmp.Diff(tt.want, c))
+
+			if got := buf.String(); len(tt.warn) > 0 {
+				if !strings.Contains(got, `"level":"warn"`) || !strings.Contains(got, tt.warn) {
+					t.Fatalf("log = %s, want a warning about %s", got, tt.warn)
+				}
+			} else if len(got) > 0 {
+				t.Fatalf("log = %s, want nothing logged", got)
+			}
+
+			if strings.Contains(buf.String(), "xoxb-evil") {
+				t.Fatalf("log = %s, should not contain the field's value", buf.String())
+			}
+
+			if cmds := f.Commands(); len(cmds) == 0 || cmds[len(cmds)-1] != "hgetall gopher:config" {
+				t.Fatalf("commands = %q, want hgetall gopher:config", cmds)
+			}
+		})
+	}
+}
+
+func TestC_OverlayFromRedis_sharedFeatures(t *testing.T) {
+	f := newFakeRedis(t)
+	defer f.Close()
+
+	f.SetHash(map[string]string{"feature:debugcmd": "on"})
+
+	client := redis.NewClient(&redis.Options{Addr: f.Addr()})
+	defer func() { _ = client.Close() }()
+
+	shared := map[string]bool{FeatureDebugCommand: false}
+	c := C{EnvFeatures: shared}
+
+	err := c.OverlayFromRedis(context.Background(), client, "k")
+	testErrCheck(t, "OverlayFromRedis()", "", err)
+
+	if !c.FeatureEnabled(FeatureDebugCommand) {
+		t.Fatal("FeatureEnabled(debugcmd) = false, want the overlay to turn it on")
+	}
+
+	if shared[FeatureDebugCommand] {
+		t.Fatal("OverlayFromRedis() modified the original EnvFeatures map")
+	}
+}
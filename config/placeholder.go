@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PlaceholderPatterns are the substrings that mark a secret as a placeholder
+// left over from a template (e.g., "CHANGEME" or "xoxb-xxxxxxxx"), rather than
+// a real value. They're matched case-insensitively, and must be lowercase.
+// Append to it, before loading the configuration, to catch more.
+var PlaceholderPatterns = []string{"changeme", "xxxxx", "replace", "todo"}
+
+// placeholderSecrets returns a problem for each non-empty secret that
+// contains one of PlaceholderPatterns. They're errors in Production, and
+// warnings otherwise. The secrets aren't included, in case they're real.
+func (c C) placeholderSecrets() []string {
+	return placeholderFields(nil, "", reflect.ValueOf(c))
+}
+
+// placeholderFields appends the problems with the secret fields of the
+// struct v to p, with their names under prefix.
+func placeholderFields(p []string, prefix string, v reflect.Value) []string {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+
+		switch {
+		case isIgnored(f):
+			continue
+
+		case fv.Kind() == reflect.Struct:
+			p = placeholderFields(p, prefix+f.Name+".", fv)
+
+		case isSecret(f) && fv.Kind() == reflect.String && fv.Len() > 0:
+			s := strings.ToLower(fv.String())
+
+			for _, pat := range PlaceholderPatterns {
+				if strings.Contains(s, pat) {
+					p = append(p, fmt.Sprintf("%s%s looks like a placeholder as it contains %q, set it to the real secret", prefix, f.Name, pat))
+					break
+				}
+			}
+		}
+	}
+
+	return p
+}
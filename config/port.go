@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// parsePortRange parses a port range like "8080-8090", inclusive of both ends.
+func parsePortRange(s string) (lo, hi uint16, err error) {
+	parts := strings.SplitN(s, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expected a range like 8080-8090, got %q", s)
+	}
+
+	l, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	h, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if l == 0 || l > h {
+		return 0, 0, fmt.Errorf("invalid range %d-%d", l, h)
+	}
+
+	return uint16(l), uint16(h), nil
+}
+
+// firstFreePort returns the first port from lo to hi that can be listened on,
+// which it checks by briefly binding a listener. Another process could still
+// take the port before we listen on it for real, but that's good enough for
+// running a few instances locally.
+func firstFreePort(lo, hi uint16) (uint16, error) {
+	for p := uint32(lo); p <= uint32(hi); p++ {
+		l, err := net.Listen("tcp", ":"+strconv.FormatUint(uint64(p), 10))
+		if err != nil {
+			continue
+		}
+
+		_ = l.Close()
+
+		return uint16(p), nil
+	}
+
+	return 0, fmt.Errorf("no free port in %d-%d", lo, hi)
+}
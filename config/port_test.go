@@ -0,0 +1,71 @@
+package config
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func Test_parsePortRange(t *testing.T) {
+	tests := []struct {
+		in     string
+		lo, hi uint16
+		err    string
+	}{
+		{in: "8080-8090", lo: 8080, hi: 8090},
+		{in: "8080 - 8080", lo: 8080, hi: 8080},
+		{in: "8080", err: `expected a range like 8080-8090, got "8080"`},
+		{in: "8090-8080", err: "invalid range 8090-8080"},
+		{in: "0-10", err: "invalid range 0-10"},
+		{in: "8080-99999", err: "value out of range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			lo, hi, err := parsePortRange(tt.in)
+			if cont := testErrCheck(t, "parsePortRange()", tt.err, err); !cont {
+				return
+			}
+
+			if lo != tt.lo || hi != tt.hi {
+				t.Fatalf("parsePortRange() = %d-%d, want %d-%d", lo, hi, tt.lo, tt.hi)
+			}
+		})
+	}
+}
+
+func TestLoadEnv_portRange(t *testing.T) {
+	// occupy a port, and use it as the start of the range
+	l, err := net.Listen("tcp", ":0")
+	testErrCheck(t, "net.Listen()", "", err)
+
+	defer func() { _ = l.Close() }()
+
+	first := l.Addr().(*net.TCPAddr).Port
+	pr := strconv.Itoa(first) + "-" + strconv.Itoa(first+20)
+
+	_ = os.Setenv("GOPHER_PORT_RANGE", pr)
+
+	defer func() {
+		_ = os.Unsetenv("GOPHER_PORT_RANGE")
+		_ = os.Unsetenv("ENV")
+	}()
+
+	c, err := LoadEnv()
+	testErrCheck(t, "LoadEnv()", "", err)
+
+	if int(c.Port) <= first || int(c.Port) > first+20 {
+		t.Fatalf("Port = %d, want a free port in %s other than the occupied %d", c.Port, pr, first)
+	}
+
+	// only Development honors the range
+	_ = os.Setenv("ENV", "staging")
+
+	c, err = LoadEnv()
+	testErrCheck(t, "LoadEnv()", "", err)
+
+	if c.Port != 0 {
+		t.Fatalf("Port = %d in staging, want 0 as GOPHER_PORT_RANGE is ignored", c.Port)
+	}
+}
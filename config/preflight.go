@@ -0,0 +1,177 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// slackAPIURL is the base URL of the Slack Web API, if Slack.APIURL isn't
+// set.
+const slackAPIURL = "https://slack.com/api"
+
+// slackAuthTest calls the Slack auth.test API method with the bot access
+// token. Like the client built by slackclient.New, it uses Slack.APIURL,
+// Slack.UserAgent, and HTTPClient if they're set.
+func (c C) slackAuthTest(ctx context.Context) error {
+	base := slackAPIURL
+	if len(c.Slack.APIURL) > 0 {
+		base = strings.TrimSuffix(c.Slack.APIURL, "/")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, base+"/auth.test", nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.Slack.BotAccessToken)
+
+	// matches slackclient.DefaultUserAgent
+	ua := c.Slack.UserAgent
+	if len(ua) == 0 {
+		ua = "gopherbot"
+	}
+
+	req.Header.Set("User-Agent", ua)
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+
+	var r struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !r.OK {
+		return errors.New(r.Error)
+	}
+
+	return nil
+}
+
+// PreflightError is the error returned by Preflight, with each of the checks
+// that failed as a *CheckError.
+type PreflightError struct {
+	Errors []error
+}
+
+func (e *PreflightError) Error() string {
+	s := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		s[i] = err.Error()
+	}
+
+	return "preflight failed: " + strings.Join(s, "; ")
+}
+
+// Preflight checks that the configuration is actually usable, by PINGing Redis
+// and calling the Slack auth.test API method with BotAccessToken
+// concurrently. The Redis check is bounded by the RedisInteractive timeout
+// (see RedisContext), and both stop early if ctx is cancelled. The Slack check
+// is skipped if SlackEnabled is false.
+//
+// If any checks fail, the error is a *PreflightError with the details of each.
+func (c C) Preflight(ctx context.Context) error {
+	checks := []func(context.Context) error{
+		NamedCheck("redis", c.preflightRedis),
+	}
+
+	if c.SlackEnabled() {
+		checks = append(checks, NamedCheck("slack", c.preflightSlack))
+	}
+
+	errs := make([]error, len(checks))
+
+	var wg sync.WaitGroup
+
+	for i, check := range checks {
+		wg.Add(1)
+
+		go func(i int, check func(context.Context) error) {
+			defer wg.Done()
+			errs[i] = check(ctx)
+		}(i, check)
+	}
+
+	wg.Wait()
+
+	var pe PreflightError
+
+	for _, err := range errs {
+		if err != nil {
+			pe.Errors = append(pe.Errors, err)
+		}
+	}
+
+	if len(pe.Errors) > 0 {
+		return &pe
+	}
+
+	return nil
+}
+
+func (c C) preflightRedis(ctx context.Context) error {
+	ctx, cancel := c.RedisContext(ctx, RedisInteractive)
+	defer cancel()
+
+	rc := redis.NewClient(DefaultRedis(c))
+	defer func() { _ = rc.Close() }()
+
+	// the vendored go-redis doesn't honor ctx, so wait for it ourselves
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- rc.WithContext(ctx).Ping().Err() }()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("failed to PING %s: %w", c.Redis.Addr, err)
+		}
+
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c C) preflightSlack(ctx context.Context) error {
+	if len(c.Slack.BotAccessToken) == 0 {
+		return errors.New("Slack.BotAccessToken is not set")
+	}
+
+	if err := c.slackAuthTest(ctx); err != nil {
+		return fmt.Errorf("auth.test failed: %w", err)
+	}
+
+	return nil
+}
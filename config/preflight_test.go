@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFakeSlack returns a server for the Slack auth.test API method under
+// /api, which accepts xoxb-good and blocks until the request is cancelled for
+// xoxb-slow. calls counts the requests it serves.
+func newFakeSlack(t *testing.T) (srv *httptest.Server, calls *int32) {
+	t.Helper()
+
+	calls = new(int32)
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+
+		if r.URL.Path != "/api/auth.test" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch r.Header.Get("Authorization") {
+		case "Bearer xoxb-good":
+			fmt.Fprint(w, `{"ok": true}`)
+		case "Bearer xoxb-slow":
+			<-r.Context().Done()
+		default:
+			fmt.Fprint(w, `{"ok": false, "error": "invalid_auth"}`)
+		}
+	}))
+
+	return srv, calls
+}
+
+func TestC_Preflight(t *testing.T) {
+	f := newFakeRedis(t)
+	defer f.Close()
+
+	slack, _ := newFakeSlack(t)
+	defer slack.Close()
+
+	cfg := C{
+		Redis: R{Addr: f.Addr(), Insecure: true},
+		Slack: S{BotAccessToken: "xoxb-good", APIURL: slack.URL + "/api/"},
+	}
+
+	err := cfg.Preflight(context.Background())
+	testErrCheck(t, "Preflight()", "", err)
+
+	cfg.Redis.Addr = "127.0.0.1:1"
+	cfg.Slack.BotAccessToken = "xoxb-bad"
+
+	err = cfg.Preflight(context.Background())
+
+	var pe *PreflightError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Preflight() error = %v, want a *PreflightError", err)
+	}
+
+	if len(pe.Errors) != 2 {
+		t.Fatalf("Preflight() returned %d errors, want 2: %v", len(pe.Errors), err)
+	}
+
+	for i, name := range []string{"redis", "slack"} {
+		var ce *CheckError
+		if !errors.As(pe.Errors[i], &ce) || ce.Name != name {
+			t.Errorf("error %d = %v, want the %s check", i, pe.Errors[i], name)
+		}
+	}
+
+	testErrCheck(t, "Preflight()", "slack check failed: auth.test failed: invalid_auth", err)
+}
+
+func TestC_Preflight_slackDisabled(t *testing.T) {
+	f := newFakeRedis(t)
+	defer f.Close()
+
+	slack, calls := newFakeSlack(t)
+	defer slack.Close()
+
+	cfg := C{
+		Redis: R{Addr: f.Addr(), Insecure: true},
+		Slack: S{Disabled: true, APIURL: slack.URL + "/api"},
+	}
+
+	err := cfg.Preflight(context.Background())
+	testErrCheck(t, "Preflight()", "", err)
+
+	if n := atomic.LoadInt32(calls); n != 0 {
+		t.Fatalf("Preflight() called Slack %d times, want 0 while it's disabled", n)
+	}
+}
+
+// recordingTransport is an http.RoundTripper that records the requests it
+// sends.
+type recordingTransport struct {
+	reqs []*http.Request
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.reqs = append(rt.reqs, r)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestC_Preflight_slackClient(t *testing.T) {
+	f := newFakeRedis(t)
+	defer f.Close()
+
+	slack, calls := newFakeSlack(t)
+	defer slack.Close()
+
+	rt := &recordingTransport{}
+
+	cfg := C{
+		Redis:      R{Addr: f.Addr(), Insecure: true},
+		Slack:      S{BotAccessToken: "xoxb-good", APIURL: slack.URL + "/api", UserAgent: "gopher-test"},
+		HTTPClient: &http.Client{Transport: rt},
+	}
+
+	err := cfg.Preflight(context.Background())
+	testErrCheck(t, "Preflight()", "", err)
+
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Fatalf("Preflight() called the Slack.APIURL server %d times, want 1", n)
+	}
+
+	if len(rt.reqs) != 1 {
+		t.Fatalf("HTTPClient sent %d requests, want 1", len(rt.reqs))
+	}
+
+	if ua := rt.reqs[0].Header.Get("User-Agent"); ua != "gopher-test" {
+		t.Fatalf("User-Agent = %q, want gopher-test", ua)
+	}
+
+	if u := rt.reqs[0].URL.String(); !strings.HasPrefix(u, slack.URL+"/api/auth.test") {
+		t.Fatalf("request URL = %q, want Slack.APIURL's auth.test", u)
+	}
+}
+
+func TestC_Preflight_cancelled(t *testing.T) {
+	f := newFakeRedis(t)
+	defer f.Close()
+
+	slack, _ := newFakeSlack(t)
+	defer slack.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	cfg := C{
+		Redis: R{Addr: f.Addr(), Insecure: true},
+		Slack: S{BotAccessToken: "xoxb-slow", APIURL: slack.URL + "/api"},
+	}
+
+	err := cfg.Preflight(ctx)
+	testErrCheck(t, "Preflight()", "slack check failed: auth.test failed", err)
+	testErrCheck(t, "Preflight()", "context deadline exceeded", err)
+
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("Preflight() took %s, it should stop when ctx is done", d)
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+const (
+	// ProfileLowTraffic is the profile for deployments handling few events,
+	// like staging, which keeps few Redis connections open.
+	ProfileLowTraffic = "low-traffic"
+
+	// ProfileNormal is the profile matching the defaults.
+	ProfileNormal = "normal"
+
+	// ProfileHighTraffic is the profile for busy workspaces, with larger
+	// pools and more concurrency.
+	ProfileHighTraffic = "high-traffic"
+)
+
+// profile is a bundle of settings that are tuned together.
+type profile struct {
+	poolSize           int
+	minIdleConns       int
+	eventsPerCPU       int
+	interactiveTimeout time.Duration
+	batchTimeout       time.Duration
+}
+
+var profiles = map[string]profile{
+	ProfileLowTraffic: {
+		poolSize:           5,
+		minIdleConns:       1,
+		eventsPerCPU:       2,
+		interactiveTimeout: 2 * time.Second,
+		batchTimeout:       30 * time.Second,
+	},
+	ProfileNormal: {
+		poolSize:           20,
+		minIdleConns:       5,
+		eventsPerCPU:       4,
+		interactiveTimeout: 2 * time.Second,
+		batchTimeout:       30 * time.Second,
+	},
+	ProfileHighTraffic: {
+		poolSize:           50,
+		minIdleConns:       10,
+		eventsPerCPU:       8,
+		interactiveTimeout: time.Second,
+		batchTimeout:       time.Minute,
+	},
+}
+
+// ApplyProfile sets the Redis pool size, Redis operation timeouts, and event
+// concurrency of c to those of the named built-in profile (ProfileLowTraffic,
+// ProfileNormal, or ProfileHighTraffic). LoadEnv applies GOPHER_PROFILE before
+// reading the individual settings, so they can still be overridden.
+func ApplyProfile(c *C, name string) error {
+	p, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+
+	c.Profile = name
+	c.Redis.PoolSize = p.poolSize
+	c.Redis.MinIdleConns = p.minIdleConns
+	c.Redis.InteractiveTimeout = p.interactiveTimeout
+	c.Redis.BatchTimeout = p.batchTimeout
+	c.MaxConcurrentEvents = runtime.NumCPU() * p.eventsPerCPU
+
+	return nil
+}
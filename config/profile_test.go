@@ -0,0 +1,38 @@
+package config
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestApplyProfile(t *testing.T) {
+	var c C
+
+	testErrCheck(t, "ApplyProfile()", "", ApplyProfile(&c, ProfileHighTraffic))
+
+	if c.Profile != ProfileHighTraffic {
+		t.Errorf("Profile = %q, want %q", c.Profile, ProfileHighTraffic)
+	}
+
+	if c.Redis.PoolSize != 50 || c.Redis.MinIdleConns != 10 {
+		t.Errorf("Redis pool = %d/%d, want 50/10", c.Redis.PoolSize, c.Redis.MinIdleConns)
+	}
+
+	if c.Redis.InteractiveTimeout != time.Second || c.Redis.BatchTimeout != time.Minute {
+		t.Errorf("Redis timeouts = %s/%s, want 1s/1m", c.Redis.InteractiveTimeout, c.Redis.BatchTimeout)
+	}
+
+	if c.MaxConcurrentEvents != runtime.NumCPU()*8 {
+		t.Errorf("MaxConcurrentEvents = %d, want %d", c.MaxConcurrentEvents, runtime.NumCPU()*8)
+	}
+
+	for name := range profiles {
+		c := validC()
+
+		testErrCheck(t, "ApplyProfile()", "", ApplyProfile(&c, name))
+		testErrCheck(t, "Validate()", "", c.Validate())
+	}
+
+	testErrCheck(t, "ApplyProfile()", `unknown profile "huge"`, ApplyProfile(&c, "huge"))
+}
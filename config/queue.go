@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OverflowPolicy is what the delayed response queue does when it's full.
+type OverflowPolicy string
+
+const (
+	// QueueBlock is when queueing a response waits for there to be room.
+	QueueBlock OverflowPolicy = "block"
+
+	// QueueDrop is when the response is discarded.
+	QueueDrop OverflowPolicy = "drop"
+
+	// QueueError is when queueing the response fails with an error.
+	QueueError OverflowPolicy = "error"
+)
+
+const defaultQueueMaxDepth = 1000
+
+// parseOverflowPolicy parses an OverflowPolicy, in any case.
+func parseOverflowPolicy(s string) (OverflowPolicy, error) {
+	switch p := OverflowPolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case QueueBlock, QueueDrop, QueueError:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown overflow policy %q, expected %s, %s, or %s", s, QueueBlock, QueueDrop, QueueError)
+	}
+}
+
+// QueueConfig are the settings the async dispatcher builds the delayed
+// response queue with, with the defaults from C.Queue already applied.
+type QueueConfig struct {
+	// MaxDepth is how many responses may be queued
+	MaxDepth int
+
+	// OverflowPolicy is what happens when the queue is full
+	OverflowPolicy OverflowPolicy
+}
+
+// QueueConfig returns the settings to build the delayed response queue with.
+// By default it's bounded at 1000 responses, and blocks when full.
+func (c C) QueueConfig() QueueConfig {
+	qc := QueueConfig{
+		MaxDepth:       c.Queue.MaxDepth,
+		OverflowPolicy: c.Queue.OverflowPolicy,
+	}
+
+	if qc.MaxDepth <= 0 {
+		qc.MaxDepth = defaultQueueMaxDepth
+	}
+
+	if len(qc.OverflowPolicy) == 0 {
+		qc.OverflowPolicy = QueueBlock
+	}
+
+	return qc
+}
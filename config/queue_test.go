@@ -0,0 +1,56 @@
+package config
+
+import "testing"
+
+func Test_parseOverflowPolicy(t *testing.T) {
+	tests := []struct {
+		in   string
+		want OverflowPolicy
+		err  string
+	}{
+		{in: "block", want: QueueBlock},
+		{in: "DROP", want: QueueDrop},
+		{in: " error ", want: QueueError},
+		{in: "reject", err: `unknown overflow policy "reject", expected block, drop, or error`},
+		{in: "", err: `unknown overflow policy ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseOverflowPolicy(tt.in)
+			if cont := testErrCheck(t, "parseOverflowPolicy()", tt.err, err); !cont {
+				return
+			}
+
+			if got != tt.want {
+				t.Fatalf("parseOverflowPolicy(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestC_QueueConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		c    C
+		want QueueConfig
+	}{
+		{
+			name: "defaults",
+			want: QueueConfig{MaxDepth: 1000, OverflowPolicy: QueueBlock},
+		},
+		{
+			name: "configured",
+			c:    C{Queue: Queue{MaxDepth: 50, OverflowPolicy: QueueDrop}},
+			want: QueueConfig{MaxDepth: 50, OverflowPolicy: QueueDrop},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.QueueConfig(); got != tt.want {
+				t.Fatalf("QueueConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// maxRateLimitKeys is how many distinct keys a rateLimitHook tracks before it
+// starts pruning those whose window has passed.
+const maxRateLimitKeys = 1024
+
+// rateLimitHook is a zerolog.Hook that discards events once more than perKey
+// with the same message have been logged in the current window.
+type rateLimitHook struct {
+	perKey int
+	window time.Duration
+
+	mu   sync.Mutex
+	keys map[string]rateLimitWindow
+}
+
+type rateLimitWindow struct {
+	start time.Time
+	count int
+}
+
+func (h *rateLimitHook) Run(e *zerolog.Event, _ zerolog.Level, msg string) {
+	if !h.allow(msg) {
+		e.Discard()
+	}
+}
+
+func (h *rateLimitHook) allow(key string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := clock.Now()
+
+	w, ok := h.keys[key]
+	if !ok || now.Sub(w.start) >= h.window {
+		if !ok && len(h.keys) >= maxRateLimitKeys {
+			h.prune(now)
+		}
+
+		w = rateLimitWindow{start: now}
+	}
+
+	w.count++
+	h.keys[key] = w
+
+	return w.count <= h.perKey
+}
+
+// prune deletes the keys whose window has passed. h.mu must be held.
+func (h *rateLimitHook) prune(now time.Time) {
+	for k, w := range h.keys {
+		if now.Sub(w.start) >= h.window {
+			delete(h.keys, k)
+		}
+	}
+}
+
+// RateLimitedLogger returns a child of base that drops repeated log lines,
+// allowing at most perKey events with the same message in each window. Unlike
+// sampling, it's keyed by the message, so one noisy error can't suppress
+// distinct ones: log the variable details as fields, not in the message.
+//
+// C.LogRateLimit and C.LogRateWindow override perKey and window when set.
+func (c C) RateLimitedLogger(base zerolog.Logger, perKey int, window time.Duration) zerolog.Logger {
+	if c.LogRateLimit > 0 {
+		perKey = c.LogRateLimit
+	}
+
+	if c.LogRateWindow > 0 {
+		window = c.LogRateWindow
+	}
+
+	return base.Hook(&rateLimitHook{
+		perKey: perKey,
+		window: window,
+		keys:   make(map[string]rateLimitWindow),
+	})
+}
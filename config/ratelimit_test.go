@@ -0,0 +1,73 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestC_RateLimitedLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := C{}.RateLimitedLogger(zerolog.New(&buf), 10, time.Minute)
+
+	for i := 0; i < 11; i++ {
+		logger.Error().Int("attempt", i).Msg("redis unavailable")
+	}
+
+	logger.Error().Msg("slack unavailable")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 11 {
+		t.Fatalf("got %d log lines, want 11:\n%s", len(lines), buf.String())
+	}
+
+	if strings.Contains(buf.String(), `"attempt":10`) {
+		t.Fatal("11th identical log line within the window was not dropped")
+	}
+
+	if !strings.Contains(lines[10], "slack unavailable") {
+		t.Fatalf("distinct log line was dropped, last line = %s", lines[10])
+	}
+}
+
+func TestC_RateLimitedLogger_override(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := C{LogRateLimit: 1}.RateLimitedLogger(zerolog.New(&buf), 10, time.Minute)
+
+	logger.Info().Msg("a")
+	logger.Info().Msg("a")
+
+	if n := strings.Count(buf.String(), "\n"); n != 1 {
+		t.Fatalf("got %d log lines, want 1", n)
+	}
+}
+
+func Test_rateLimitHook_window(t *testing.T) {
+	clk := NewFakeClock(time.Unix(1586000000, 0))
+	defer SetClockForTest(clk)()
+
+	h := &rateLimitHook{
+		perKey: 1,
+		window: time.Minute,
+		keys:   make(map[string]rateLimitWindow),
+	}
+
+	if !h.allow("k") {
+		t.Fatal("allow() = false for the first event")
+	}
+
+	if h.allow("k") {
+		t.Fatal("allow() = true beyond perKey within the window")
+	}
+
+	clk.Advance(time.Minute)
+
+	if !h.allow("k") {
+		t.Fatal("allow() = false once the window passed")
+	}
+}
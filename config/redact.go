@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// redactedValue is what secret values are replaced with when redacted.
+const redactedValue = "****"
+
+// isSecret returns whether the struct field holds a secret value, which is
+// marked by the `secret:"true"` struct tag.
+func isSecret(f reflect.StructField) bool {
+	return f.Tag.Get("secret") == "true"
+}
+
+// isIgnored returns whether the struct field should be skipped when
+// inspecting the configuration, because it's not a plain value (e.g., a hook
+// function). These are tagged with `json:"-"`.
+func isIgnored(f reflect.StructField) bool {
+	return f.Tag.Get("json") == "-"
+}
+
+// redact walks the struct v, replacing any non-empty secret string fields
+// with redactedValue. v must be addressable.
+func redact(v reflect.Value) {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+
+		switch {
+		case isIgnored(f):
+			continue
+
+		case fv.Kind() == reflect.Struct:
+			redact(fv)
+
+		case isSecret(f) && fv.Kind() == reflect.String && fv.Len() > 0:
+			fv.SetString(redactedValue)
+		}
+	}
+}
+
+// Redacted returns a copy of the configuration with all secret values
+// replaced with "****", making it safe to log or display. Secrets that are
+// empty are left empty, so you can still tell whether they were set.
+func (c C) Redacted() C {
+	redact(reflect.ValueOf(&c).Elem())
+	return c
+}
+
+// String satisfies fmt.Stringer, and returns the redacted configuration so
+// that secrets aren't accidentally leaked by printing it.
+func (c C) String() string {
+	type plain C // avoid recursing in to this method
+	return fmt.Sprintf("%+v", plain(c.Redacted()))
+}
+
+// secretValues walks the struct v, returning the values of the non-empty
+// secret string fields.
+func secretValues(v reflect.Value) []string {
+	var s []string
+
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+
+		switch {
+		case isIgnored(f):
+			continue
+
+		case fv.Kind() == reflect.Struct:
+			s = append(s, secretValues(fv)...)
+
+		case isSecret(f) && fv.Kind() == reflect.String && fv.Len() > 0:
+			s = append(s, fv.String())
+		}
+	}
+
+	return s
+}
+
+// RedactString returns s with any of the configured secret values in it
+// replaced with "****", so that strings from elsewhere (e.g., an upstream
+// error body) can be displayed safely. Where secrets overlap, the longest is
+// replaced.
+func (c C) RedactString(s string) string {
+	secrets := secretValues(reflect.ValueOf(c))
+	if len(secrets) == 0 {
+		return s
+	}
+
+	// the Replacer picks the first matching old string at each position,
+	// so putting longer secrets first replaces them whole
+	sort.SliceStable(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+
+	pairs := make([]string, 0, len(secrets)*2)
+	for _, v := range secrets {
+		pairs = append(pairs, v, redactedValue)
+	}
+
+	return strings.NewReplacer(pairs...).Replace(s)
+}
@@ -0,0 +1,340 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const (
+	// RedisInteractive is the RedisContext class for operations on the
+	// request path, which should fail fast.
+	RedisInteractive = "interactive"
+
+	// RedisBatch is the RedisContext class for long-running operations in
+	// background jobs, like large SCANs.
+	RedisBatch = "batch"
+
+	defaultRedisPoolSize = 20
+
+	// maxScaledPoolSize caps the pool size scaledPoolSize picks, so that a
+	// large concurrency value can't exhaust the Redis server's connections.
+	maxScaledPoolSize = 200
+
+	defaultRedisDialTimeout        = 2 * time.Second
+	defaultRedisReadTimeout        = 2 * time.Second
+	defaultRedisInteractiveTimeout = 2 * time.Second
+	defaultRedisBatchTimeout       = 30 * time.Second
+)
+
+// scaledPoolSize scales the default Redis pool size to the dyno, using its
+// web concurrency (GOPHER_WEB_CONCURRENCY, or Heroku's WEB_CONCURRENCY
+// convention), which is larger on bigger dynos:
+//
+//	PoolSize = min(base * concurrency, 200)
+//
+// where base is the pool size set by the profile, or 20. A concurrency of 1,
+// as on a standard-1X dyno, leaves the default unchanged.
+func scaledPoolSize(base, concurrency int) int {
+	if base <= 0 {
+		base = defaultRedisPoolSize
+	}
+
+	if n := base * concurrency; n < maxScaledPoolSize {
+		return n
+	}
+
+	return maxScaledPoolSize
+}
+
+// FailMode is how a feature should behave when Redis is unavailable.
+type FailMode string
+
+const (
+	// RedisFailOpen is when features should degrade gracefully and carry on
+	// without Redis.
+	RedisFailOpen FailMode = "open"
+
+	// RedisFailClosed is when features should reject the request if Redis
+	// is unavailable.
+	RedisFailClosed FailMode = "closed"
+)
+
+// FailClosed returns whether handlers should reject requests, rather than
+// degrade gracefully, when Redis errors occur.
+func (c C) FailClosed() bool {
+	return c.RedisFailMode != RedisFailOpen
+}
+
+// RedisContext returns a context derived from parent with the deadline for the
+// class of Redis operation (RedisInteractive or RedisBatch). Unknown classes
+// are treated as RedisInteractive.
+//
+// The vendored go-redis client doesn't apply context deadlines to the socket,
+// so the client-wide ReadTimeout still bounds each individual command. The
+// deadline bounds the operation as a whole, and callers issuing many commands
+// (e.g., iterating a SCAN) should check ctx.Err() between them.
+func (c C) RedisContext(parent context.Context, class string) (context.Context, context.CancelFunc) {
+	timeout := c.Redis.InteractiveTimeout
+	if timeout <= 0 {
+		timeout = defaultRedisInteractiveTimeout
+	}
+
+	if class == RedisBatch {
+		timeout = c.Redis.BatchTimeout
+		if timeout <= 0 {
+			timeout = defaultRedisBatchTimeout
+		}
+	}
+
+	return context.WithTimeout(parent, timeout)
+}
+
+// redisResolvingDialer returns a dialer for go-redis that resolves the Redis
+// host using rc.Resolver (or the system resolver) bounded by rc.LookupTimeout,
+// before connecting to each of the resolved addresses in turn. It otherwise
+// behaves like the go-redis default dialer, including negotiating TLS when
+// o.TLSConfig is set.
+func redisResolvingDialer(rc R, o *redis.Options) func() (net.Conn, error) {
+	return func() (net.Conn, error) {
+		host, port, err := net.SplitHostPort(o.Addr)
+		if err != nil {
+			return nil, err
+		}
+
+		resolver := rc.Resolver
+		if resolver == nil {
+			resolver = net.DefaultResolver
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), o.DialTimeout)
+		defer cancel()
+
+		lctx := ctx
+
+		if rc.LookupTimeout > 0 {
+			var lcancel context.CancelFunc
+
+			lctx, lcancel = context.WithTimeout(ctx, rc.LookupTimeout)
+			defer lcancel()
+		}
+
+		addrs, err := resolver.LookupHost(lctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		d := &net.Dialer{KeepAlive: 5 * time.Minute}
+
+		var conn net.Conn
+
+		for _, addr := range addrs {
+			conn, err = d.DialContext(ctx, o.Network, net.JoinHostPort(addr, port))
+			if err == nil {
+				break
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if o.TLSConfig == nil {
+			return conn, nil
+		}
+
+		tc := o.TLSConfig.Clone()
+		if len(tc.ServerName) == 0 {
+			tc.ServerName = host
+		}
+
+		tlsConn := tls.Client(conn, tc)
+
+		if dl, ok := ctx.Deadline(); ok {
+			_ = tlsConn.SetDeadline(dl)
+		}
+
+		if err := tlsConn.Handshake(); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+
+		_ = tlsConn.SetDeadline(time.Time{})
+
+		return tlsConn, nil
+	}
+}
+
+const (
+	// RedisURLSourceAuto is when the Redis URL is loaded from REDIS_TLS_URL if
+	// it's set, falling back to REDIS_URL.
+	RedisURLSourceAuto = "auto"
+
+	// RedisURLSourceTLS is when the Redis URL is only loaded from
+	// REDIS_TLS_URL.
+	RedisURLSourceTLS = "tls"
+
+	// RedisURLSourcePlain is when the Redis URL is only loaded from
+	// REDIS_URL, even if REDIS_TLS_URL is set.
+	RedisURLSourcePlain = "plain"
+)
+
+// redisURL returns the Redis URL to load and the environment variable it came
+// from, based on GOPHER_REDIS_URL_SOURCE (defaulting to RedisURLSourceAuto).
+// REDIS_TLS_URL is always treated as a rediss:// URL, even if Heroku hands it
+// out as redis://, so that its port is used as it is.
+func redisURL(getenv func(string) string) (key string, u string, err error) {
+	src := strings.ToLower(getenv("GOPHER_REDIS_URL_SOURCE"))
+
+	switch src {
+	case "", RedisURLSourceAuto, RedisURLSourceTLS:
+		if tu := getenv("REDIS_TLS_URL"); len(tu) > 0 || src == RedisURLSourceTLS {
+			if strings.HasPrefix(tu, "redis://") {
+				tu = "rediss://" + strings.TrimPrefix(tu, "redis://")
+			}
+
+			return "REDIS_TLS_URL", tu, nil
+		}
+
+		return "REDIS_URL", getenv("REDIS_URL"), nil
+
+	case RedisURLSourcePlain:
+		return "REDIS_URL", getenv("REDIS_URL"), nil
+
+	default:
+		return "", "", fmt.Errorf("failed to parse GOPHER_REDIS_URL_SOURCE: unknown source %q, expected %s, %s, or %s", src, RedisURLSourceAuto, RedisURLSourceTLS, RedisURLSourcePlain)
+	}
+}
+
+// redisCustomDialer adapts dial to the dialer go-redis expects, connecting to
+// o.Addr with the dial timeout applied to the context. TLS is left to dial.
+func redisCustomDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error), o *redis.Options) func() (net.Conn, error) {
+	return func() (net.Conn, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), o.DialTimeout)
+		defer cancel()
+
+		conn, err := dial(ctx, o.Network, o.Addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if conn == nil {
+			return nil, fmt.Errorf("Redis dialer returned no connection to %s", o.Addr)
+		}
+
+		return conn, nil
+	}
+}
+
+// redisTLSConflict returns an error if the Redis URL u, loaded from the
+// environment variable key, contradicts the GOPHER_REDIS_INSECURE flag, rather
+// than quietly picking one of them: either a rediss:// URL with plaintext
+// requested, or a redis:// URL with TLS query options (an sslmode other than
+// disable, or ssl_ca_certs) while plaintext is requested.
+func redisTLSConflict(key, u string, insecure bool) error {
+	if !insecure {
+		return nil
+	}
+
+	pu, err := url.Parse(u)
+	if err != nil {
+		return nil // reported by secureRedisCredentials
+	}
+
+	if pu.Scheme == "rediss" {
+		return fmt.Errorf("%s uses rediss:// but GOPHER_REDIS_INSECURE=1 requests plaintext", key)
+	}
+
+	q := pu.Query()
+
+	// sslmode=disable explicitly asks for plaintext, so it agrees
+	if v := q.Get("sslmode"); len(v) > 0 && !strings.EqualFold(v, "disable") {
+		return fmt.Errorf("%s requests TLS with sslmode but GOPHER_REDIS_INSECURE=1 requests plaintext", key)
+	}
+
+	if len(q.Get("ssl_ca_certs")) > 0 {
+		return fmt.Errorf("%s requests TLS with ssl_ca_certs but GOPHER_REDIS_INSECURE=1 requests plaintext", key)
+	}
+
+	return nil
+}
+
+// defaultRedisPoolMetricsInterval is the RedisPoolMetricsInterval if one isn't
+// set.
+const defaultRedisPoolMetricsInterval = 15 * time.Second
+
+// StartRedisPoolMetrics starts polling the client's connection pool stats
+// every interval (e.g., C.RedisPoolMetricsInterval), passing them to emit so
+// they can be sent to a metrics system to watch for pool saturation. It stops
+// when ctx is cancelled. If interval is zero, it's disabled and emit is never
+// called.
+func StartRedisPoolMetrics(ctx context.Context, client *redis.Client, interval time.Duration, emit func(redis.PoolStats)) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-t.C:
+				emit(*client.PoolStats())
+			}
+		}
+	}()
+}
+
+// closeRedisPollInterval is how often CloseRedis checks whether the pool has
+// drained.
+const closeRedisPollInterval = 10 * time.Millisecond
+
+// CloseRedis closes client once its in-flight commands have finished, waiting
+// until ctx is done for the pool to drain. If ctx has no deadline, it waits up
+// to the default ShutdownTimeout of 25 seconds, so use C.ShutdownContext to
+// apply the configured one. The client is closed either way, but an error is
+// returned if commands were still in flight when ctx was done.
+func CloseRedis(ctx context.Context, client *redis.Client) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, defaultShutdownTimeout)
+		defer cancel()
+	}
+
+	t := time.NewTicker(closeRedisPollInterval)
+	defer t.Stop()
+
+	for {
+		s := client.PoolStats()
+
+		inUse := s.TotalConns - s.IdleConns
+		if inUse == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = client.Close()
+			return fmt.Errorf("failed to close Redis cleanly, %d connections still in use: %w", inUse, ctx.Err())
+
+		case <-t.C:
+		}
+	}
+
+	if err := client.Close(); err != nil {
+		return fmt.Errorf("failed to close Redis: %w", err)
+	}
+
+	return nil
+}
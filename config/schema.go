@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldDoc describes a configuration field, for generating documentation and
+// checking configurations.
+type FieldDoc struct {
+	// Path is the field's Set path (e.g., "slack.team_id")
+	Path string `json:"path"`
+
+	// Env are the environment variables the field is loaded from, in order
+	// of precedence. It's empty for fields only set another way, such as from
+	// the Redis URL's query string.
+	Env []string `json:"env,omitempty"`
+
+	// Type is the field's Go type (e.g., "time.Duration")
+	Type string `json:"type"`
+
+	// Default is the field's value when nothing is set, formatted like Set
+	// accepts it, and empty if it's the zero value. Some defaults depend on
+	// the environment, and these are the Development ones. It's always empty
+	// for secrets.
+	Default string `json:"default,omitempty"`
+
+	// Secret is whether the field holds a secret
+	Secret bool `json:"secret"`
+}
+
+// Schema returns a FieldDoc for each configuration field, found by walking C
+// and its nested structs, in the order they're declared. The environment
+// variables come from each field's env struct tag. Fields that can't be
+// configured (tagged `json:"-"`) are skipped.
+func Schema() []FieldDoc {
+	// the error can only come from parsing environment variables, and there
+	// are none
+	defaults, _ := load(func(string) string { return "" })
+
+	return schemaFields(nil, "", reflect.ValueOf(defaults))
+}
+
+// schemaFields appends a FieldDoc for each field of the struct v to docs,
+// with their paths under prefix.
+func schemaFields(docs []FieldDoc, prefix string, v reflect.Value) []FieldDoc {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+
+		if isIgnored(f) {
+			continue
+		}
+
+		path := prefix + fieldName(f)
+
+		if fv.Kind() == reflect.Struct {
+			docs = schemaFields(docs, path+".", fv)
+			continue
+		}
+
+		d := FieldDoc{
+			Path:   path,
+			Type:   f.Type.String(),
+			Secret: isSecret(f),
+		}
+
+		if env := f.Tag.Get("env"); len(env) > 0 {
+			d.Env = strings.Split(env, ",")
+		}
+
+		if !d.Secret && !fv.IsZero() {
+			d.Default = schemaDefault(fv)
+		}
+
+		docs = append(docs, d)
+	}
+
+	return docs
+}
+
+// schemaDefault formats the default value v like Set would parse it.
+func schemaDefault(v reflect.Value) string {
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String {
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).String()
+		}
+
+		return strings.Join(items, ",")
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// envHint returns how to set the field at the Go path name (e.g.,
+// "Slack.RequestSecret"), for appending to an error about it: the first
+// environment variable from its env struct tag, and the example from its
+// example struct tag if there is one. It's empty if the field has no env tag.
+func envHint(name string) string {
+	t := reflect.TypeOf(C{})
+
+	var f reflect.StructField
+	for _, part := range strings.Split(name, ".") {
+		var ok bool
+		if f, ok = t.FieldByName(part); !ok {
+			panic(fmt.Sprintf("config: no field %q in C", name))
+		}
+
+		t = f.Type
+	}
+
+	env := f.Tag.Get("env")
+	if len(env) == 0 {
+		return ""
+	}
+
+	hint := "; set " + strings.Split(env, ",")[0]
+
+	if example := f.Tag.Get("example"); len(example) > 0 {
+		hint += fmt.Sprintf(" (e.g. %s)", example)
+	}
+
+	return hint
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSchema(t *testing.T) {
+	docs := make(map[string]FieldDoc)
+
+	for _, d := range Schema() {
+		if _, ok := docs[d.Path]; ok {
+			t.Fatalf("Schema() has duplicate path %q", d.Path)
+		}
+
+		docs[d.Path] = d
+
+		if d.Secret && len(d.Default) > 0 {
+			t.Fatalf("Schema() secret %s has a default", d.Path)
+		}
+
+		// every documented path must be settable, and its default must
+		// round-trip, except for maps which Set doesn't support
+		if d.Type == "map[string]bool" {
+			continue
+		}
+
+		var c C
+		if err := c.Set(d.Path, d.Default); err != nil && len(d.Default) > 0 {
+			t.Fatalf("Set(%q, %q) error = %v", d.Path, d.Default, err)
+		}
+	}
+
+	tests := []FieldDoc{
+		{Path: "env", Env: []string{"ENV"}, Type: "config.Environment", Default: "development"},
+		{Path: "log_level", Env: []string{"GOPHER_LOG_LEVEL"}, Type: "zerolog.Level", Default: "info"},
+		{Path: "port", Env: []string{"PORT"}, Type: "uint16"},
+		{Path: "redis.password", Env: []string{"REDIS_TLS_URL", "REDIS_URL"}, Type: "string", Secret: true},
+		{Path: "redis.ca_cert_path", Type: "string"},
+		{Path: "aws.region", Env: []string{"GOPHER_AWS_REGION", "AWS_REGION", "AWS_DEFAULT_REGION"}, Type: "string"},
+		{Path: "slack.ack_timeout", Env: []string{"GOPHER_SLACK_ACK_TIMEOUT"}, Type: "time.Duration", Default: "2.5s"},
+		{Path: "slack.bot_access_token", Env: []string{"GOPHER_SLACK_BOT_ACCESS_TOKEN"}, Type: "string", Secret: true},
+		{Path: "slack.respond_to_mentions", Env: []string{"GOPHER_SLACK_RESPOND_TO_MENTIONS"}, Type: "bool", Default: "true"},
+		{Path: "features", Env: []string{"GOPHER_FEATURE_<NAME>"}, Type: "map[string]bool"},
+	}
+
+	for _, want := range tests {
+		t.Run(want.Path, func(t *testing.T) {
+			got, ok := docs[want.Path]
+			if !ok {
+				t.Fatalf("Schema() is missing %s", want.Path)
+			}
+
+			cmpDiff(t, "FieldDoc", cmp.Diff(want, got))
+		})
+	}
+
+	// ignored fields, like hooks, are named "-"
+	for path := range docs {
+		if strings.HasSuffix(path, "-") {
+			t.Fatalf("Schema() includes ignored field %s", path)
+		}
+	}
+}
+
+func TestC_Validate_envHints(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *C)
+		env    string
+	}{
+		{
+			name: "maintenance_message",
+			modify: func(c *C) {
+				c.MaintenanceMode = true
+				c.MaintenanceMessage = ""
+			},
+			env: "GOPHER_MAINTENANCE_MESSAGE (e.g. back in 10 minutes)",
+		},
+		{
+			name:   "tls_key_file",
+			modify: func(c *C) { c.TLS.CertFile = "tls.crt" },
+			env:    "GOPHER_TLS_KEY",
+		},
+		{
+			name:   "tls_cert_file",
+			modify: func(c *C) { c.TLS.KeyFile = "tls.key" },
+			env:    "GOPHER_TLS_CERT",
+		},
+		{
+			name: "command_prefix",
+			modify: func(c *C) {
+				c.Slack.RespondToMentions = false
+				c.Slack.CommandPrefix = ""
+			},
+			env: "GOPHER_SLACK_COMMAND_PREFIX (e.g. !)",
+		},
+		{
+			name: "request_secret_events",
+			modify: func(c *C) {
+				c.Slack.TransportMode = TransportEvents
+				c.Slack.RequestSecret = ""
+			},
+			env: "GOPHER_SLACK_REQUEST_SECRET",
+		},
+		{
+			name: "app_level_token_socket",
+			modify: func(c *C) {
+				c.Slack.TransportMode = TransportSocket
+				c.Slack.AppLevelToken = ""
+			},
+			env: "GOPHER_SLACK_APP_LEVEL_TOKEN (e.g. xapp-...)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validC()
+			tt.modify(&c)
+
+			err := c.Validate()
+			if err == nil {
+				t.Fatal("Validate() error = <nil>, want error")
+			}
+
+			if want := "; set " + tt.env; !strings.Contains(err.Error(), want) {
+				t.Fatalf("Validate() error = %q, want it to contain %q", err, want)
+			}
+		})
+	}
+}
+
+func Test_envHint(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Slack.BotAccessToken", want: "; set GOPHER_SLACK_BOT_ACCESS_TOKEN (e.g. xoxb-...)"},
+		{name: "Slack.TeamID", want: "; set GOPHER_SLACK_TEAM_ID"},
+		{name: "Redis.Password", want: "; set REDIS_TLS_URL"},
+		{name: "Redis.CACertPath", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := envHint(tt.name); got != tt.want {
+				t.Fatalf("envHint(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// redisURLEnvKeys are the environment variables holding the Redis URLs, which
+// embed the Redis password. They're left set by LoadEnv, as the Redis add-ons
+// manage them.
+var redisURLEnvKeys = []string{"REDIS_URL", "REDIS_TLS_URL"}
+
+// secretEnvKeys are the environment variables holding secrets, which LoadEnv
+// unsets after reading. They're those of the fields tagged `secret:"true"`,
+// other than the Redis URLs.
+var secretEnvKeys = secretFieldEnvKeys(nil, reflect.TypeOf(C{}))
+
+// sourcedSecretKeys are the keys LoadWithSecretSource resolves using the
+// SecretSource. This includes the Redis URLs, as they embed the Redis password.
+var sourcedSecretKeys = append(append([]string(nil), redisURLEnvKeys...), secretEnvKeys...)
+
+// isRedisURLEnvKey returns whether key is one of redisURLEnvKeys.
+func isRedisURLEnvKey(key string) bool {
+	for _, k := range redisURLEnvKeys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// secretFieldEnvKeys appends the environment variables of the secret fields
+// of the struct type t to keys, skipping the Redis URLs.
+func secretFieldEnvKeys(keys []string, t reflect.Type) []string {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		switch {
+		case isIgnored(f):
+			continue
+
+		case f.Type.Kind() == reflect.Struct:
+			keys = secretFieldEnvKeys(keys, f.Type)
+
+		case isSecret(f):
+			for _, k := range strings.Split(f.Tag.Get("env"), ",") {
+				if len(k) > 0 && !isRedisURLEnvKey(k) {
+					keys = append(keys, k)
+				}
+			}
+		}
+	}
+
+	return keys
+}
+
+// SecretSource is a store that secret configuration values can be resolved
+// from, such as a cloud secret manager.
+type SecretSource interface {
+	// Resolve returns the value of the secret for key, which is the name of
+	// the environment variable LoadEnv would read it from (e.g.,
+	// GOPHER_SLACK_BOT_ACCESS_TOKEN). The bool is false if the secret isn't
+	// in the store.
+	Resolve(ctx context.Context, key string) (string, bool, error)
+}
+
+// EnvSecretSource is a SecretSource that resolves secrets from the
+// environment.
+type EnvSecretSource struct{}
+
+// Resolve satisfies SecretSource.
+func (EnvSecretSource) Resolve(_ context.Context, key string) (string, bool, error) {
+	v, ok := os.LookupEnv(key)
+	return v, ok, nil
+}
+
+var _ SecretSource = EnvSecretSource{}
+
+// BatchSecretSource is a SecretSource that can resolve many secrets at once,
+// such as from a single API call. LoadEnvWith uses ResolveAll, rather than
+// calling Resolve for each key, when the SecretSource implements it.
+type BatchSecretSource interface {
+	SecretSource
+
+	// ResolveAll returns the values of the secrets for keys, which are named
+	// the same as for Resolve. Keys that aren't in the store are left out of
+	// the map.
+	ResolveAll(ctx context.Context, keys []string) (map[string]string, error)
+}
+
+// LoadWithSecretSource loads the configuration like LoadEnv, except that
+// secrets are resolved using src. Secrets that src doesn't have, and all other
+// values, are read from the environment.
+func LoadWithSecretSource(ctx context.Context, src SecretSource) (C, error) {
+	return LoadEnvWith(ctx, LoadOptions{SecretSource: src})
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type mapSecretSource map[string]string
+
+func (m mapSecretSource) Resolve(_ context.Context, key string) (string, bool, error) {
+	if key == "GOPHER_DEBUG_TOKEN" && m["fail"] == "true" {
+		return "", false, errors.New("store unavailable")
+	}
+
+	v, ok := m[key]
+
+	return v, ok, nil
+}
+
+func TestLoadWithSecretSource(t *testing.T) {
+	_ = os.Setenv("ENV", "production")
+	_ = os.Setenv("GOPHER_SLACK_TEAM_ID", "T123")
+	_ = os.Setenv("GOPHER_SLACK_BOT_ACCESS_TOKEN", "from-env")
+	_ = os.Setenv("GOPHER_SLACK_CLIENT_SECRET", "client-from-env")
+
+	defer func() {
+		s := []string{
+			"ENV", "GOPHER_SLACK_TEAM_ID", "GOPHER_SLACK_BOT_ACCESS_TOKEN",
+			"GOPHER_SLACK_CLIENT_SECRET",
+		}
+
+		for _, v := range s {
+			_ = os.Unsetenv(v)
+		}
+	}()
+
+	src := mapSecretSource{
+		"GOPHER_SLACK_BOT_ACCESS_TOKEN": "from-source",
+		"GOPHER_SLACK_REQUEST_SECRET":   "abc",
+		"REDIS_URL":                     "redis://:pw@redis.example.org:6379",
+		"GOPHER_SLACK_TEAM_ID":          "ignored",
+	}
+
+	c, err := LoadWithSecretSource(context.Background(), src)
+	testErrCheck(t, "LoadWithSecretSource()", "", err)
+
+	if c.Slack.BotAccessToken != "from-source" {
+		t.Errorf("Slack.BotAccessToken = %q, want %q", c.Slack.BotAccessToken, "from-source")
+	}
+
+	if c.Slack.ClientSecret != "client-from-env" {
+		t.Errorf("Slack.ClientSecret = %q, want %q", c.Slack.ClientSecret, "client-from-env")
+	}
+
+	if c.Slack.TeamID != "T123" {
+		t.Errorf("Slack.TeamID = %q, want %q", c.Slack.TeamID, "T123")
+	}
+
+	if c.Redis.Password != "pw" {
+		t.Errorf("Redis.Password = %q, want %q", c.Redis.Password, "pw")
+	}
+
+	src["fail"] = "true"
+
+	_, err = LoadWithSecretSource(context.Background(), src)
+	testErrCheck(t, "LoadWithSecretSource()", "failed to resolve secret GOPHER_DEBUG_TOKEN: store unavailable", err)
+}
+
+type batchSecretSource struct {
+	mapSecretSource
+	calls int
+}
+
+func (b *batchSecretSource) Resolve(context.Context, string) (string, bool, error) {
+	return "", false, errors.New("Resolve called on a BatchSecretSource")
+}
+
+func (b *batchSecretSource) ResolveAll(_ context.Context, keys []string) (map[string]string, error) {
+	b.calls++
+
+	if b.mapSecretSource["fail"] == "true" {
+		return nil, errors.New("store unavailable")
+	}
+
+	values := make(map[string]string)
+
+	for _, k := range keys {
+		if v, ok := b.mapSecretSource[k]; ok {
+			values[k] = v
+		}
+	}
+
+	return values, nil
+}
+
+func TestLoadWithSecretSource_batch(t *testing.T) {
+	_ = os.Setenv("GOPHER_SLACK_BOT_ACCESS_TOKEN", "from-env")
+	defer func() { _ = os.Unsetenv("GOPHER_SLACK_BOT_ACCESS_TOKEN") }()
+
+	src := &batchSecretSource{mapSecretSource: mapSecretSource{
+		"GOPHER_SLACK_BOT_ACCESS_TOKEN": "from-source",
+	}}
+
+	c, err := LoadWithSecretSource(context.Background(), src)
+	testErrCheck(t, "LoadWithSecretSource()", "", err)
+
+	if c.Slack.BotAccessToken != "from-source" {
+		t.Errorf("Slack.BotAccessToken = %q, want %q", c.Slack.BotAccessToken, "from-source")
+	}
+
+	if src.calls != 1 {
+		t.Errorf("ResolveAll() called %d times, want 1", src.calls)
+	}
+
+	src.mapSecretSource["fail"] = "true"
+
+	_, err = LoadWithSecretSource(context.Background(), src)
+	testErrCheck(t, "LoadWithSecretSource()", "failed to resolve secrets: store unavailable", err)
+}
+
+func TestEnvSecretSource(t *testing.T) {
+	_ = os.Setenv("GOPHER_DEBUG_TOKEN", "debug123")
+	defer func() { _ = os.Unsetenv("GOPHER_DEBUG_TOKEN") }()
+
+	v, ok, err := EnvSecretSource{}.Resolve(context.Background(), "GOPHER_DEBUG_TOKEN")
+	testErrCheck(t, "Resolve()", "", err)
+
+	if !ok || v != "debug123" {
+		t.Fatalf("Resolve() = %q, %t, want %q, true", v, ok, "debug123")
+	}
+
+	if _, ok, _ := (EnvSecretSource{}).Resolve(context.Background(), "GOPHER_NOT_SET"); ok {
+		t.Fatal("Resolve() found an unset variable")
+	}
+}
+
+func Test_secretEnvKeys(t *testing.T) {
+	want := []string{
+		"GOPHER_SLACK_BOT_ACCESS_TOKEN",
+		"GOPHER_SLACK_CLIENT_SECRET",
+		"GOPHER_SLACK_APP_LEVEL_TOKEN",
+		"GOPHER_SLACK_REQUEST_SECRET",
+		"GOPHER_SLACK_REQUEST_TOKEN",
+		"GOPHER_DEBUG_TOKEN",
+		"GOPHER_ALERT_WEBHOOK_URL",
+	}
+
+	cmpDiff(t, "secretEnvKeys", cmp.Diff(want, secretEnvKeys))
+
+	if !isSecretEnvKey("REDIS_URL") || !isSecretEnvKey("REDIS_TLS_URL") {
+		t.Fatal("sourcedSecretKeys is missing the Redis URLs")
+	}
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	levelType    = reflect.TypeOf(zerolog.Level(0))
+	envType      = reflect.TypeOf(Environment(""))
+)
+
+// Set sets the field at path to value, converting value to the field's type.
+// The path is the field's serialized name, with nested fields separated by
+// dots (e.g., "port", "log_level", or "slack.team_id"). Durations are parsed
+// with time.ParseDuration, log levels like GOPHER_LOG_LEVEL, the environment
+// like ENV (but unknown environments are an error), booleans like the other
+// GOPHER_ toggles, and lists are comma-separated.
+//
+// Set doesn't validate the resulting configuration, so callers should call
+// Validate once they're done setting fields.
+func (c *C) Set(path, value string) error {
+	v := reflect.ValueOf(c).Elem()
+
+	for _, name := range strings.Split(path, ".") {
+		if v.Kind() != reflect.Struct {
+			return fmt.Errorf("unknown configuration path %q", path)
+		}
+
+		f, ok := fieldByName(v, name)
+		if !ok {
+			return fmt.Errorf("unknown configuration path %q", path)
+		}
+
+		v = f
+	}
+
+	if v.Kind() == reflect.Struct {
+		return fmt.Errorf("configuration path %q is not a value", path)
+	}
+
+	if err := setValue(v, value); err != nil {
+		return fmt.Errorf("failed to set %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// fieldByName returns the field of the struct v with the serialized name, as
+// returned by fieldName. Ignored fields can't be found.
+func fieldByName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		if f := t.Field(i); !isIgnored(f) && fieldName(f) == name {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// setValue parses s in to v, based on v's type.
+func setValue(v reflect.Value, s string) error {
+	switch v.Type() {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+
+		return nil
+
+	case levelType:
+		l, err := parseLogLevel(s)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(l))
+
+		return nil
+
+	case envType:
+		e, err := parseEnvironment(s)
+		if err != nil {
+			return err
+		}
+
+		v.SetString(string(e))
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+
+	case reflect.Bool:
+		b, err := parseBool(s)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(u)
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+
+		v.Set(reflect.ValueOf(splitList(s)).Convert(v.Type()))
+
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
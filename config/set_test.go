@@ -0,0 +1,135 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rs/zerolog"
+)
+
+func TestC_Set(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		value string
+		err   string
+		want  C
+	}{
+		{
+			name:  "port",
+			path:  "port",
+			value: "9090",
+			want:  C{Port: 9090},
+		},
+		{
+			name:  "env",
+			path:  "env",
+			value: "staging",
+			want:  C{Env: Staging},
+		},
+		{
+			name:  "env_capitalized",
+			path:  "env",
+			value: "Production",
+			want:  C{Env: Production},
+		},
+		{
+			name:  "env_unknown",
+			path:  "env",
+			value: "prod",
+			err:   `failed to set env: unknown environment "prod"`,
+		},
+		{
+			name:  "log_level",
+			path:  "log_level",
+			value: "debug",
+			want:  C{LogLevel: zerolog.DebugLevel},
+		},
+		{
+			name:  "nested_string",
+			path:  "slack.team_id",
+			value: "T123",
+			want:  C{Slack: S{TeamID: "T123"}},
+		},
+		{
+			name:  "nested_bool",
+			path:  "redis.insecure",
+			value: "true",
+			want:  C{Redis: R{Insecure: true}},
+		},
+		{
+			name:  "nested_bool_yes",
+			path:  "redis.insecure",
+			value: "yes",
+			want:  C{Redis: R{Insecure: true}},
+		},
+		{
+			name:  "bad_bool",
+			path:  "redis.insecure",
+			value: "y",
+			err:   `failed to set redis.insecure: invalid boolean "y"`,
+		},
+		{
+			name:  "nested_duration",
+			path:  "slack.max_timestamp_skew",
+			value: "7m",
+			want:  C{Slack: S{MaxTimestampSkew: 7 * time.Minute}},
+		},
+		{
+			name:  "nested_list",
+			path:  "redis.tls_cipher_suites",
+			value: "TLS_AES_128_GCM_SHA256, TLS_AES_256_GCM_SHA384",
+			want:  C{Redis: R{TLSCipherSuites: []string{"TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384"}}},
+		},
+		{
+			name:  "port_out_of_range",
+			path:  "port",
+			value: "70000",
+			err:   `failed to set port: strconv.ParseUint: parsing "70000": value out of range`,
+		},
+		{
+			name:  "bad_duration",
+			path:  "slack.max_timestamp_skew",
+			value: "7",
+			err:   `failed to set slack.max_timestamp_skew: time: missing unit in duration "7"`,
+		},
+		{
+			name:  "unknown",
+			path:  "slack.made_up",
+			value: "x",
+			err:   `unknown configuration path "slack.made_up"`,
+		},
+		{
+			name:  "too_deep",
+			path:  "port.number",
+			value: "1",
+			err:   `unknown configuration path "port.number"`,
+		},
+		{
+			name:  "struct",
+			path:  "slack",
+			value: "x",
+			err:   `configuration path "slack" is not a value`,
+		},
+		{
+			name:  "ignored",
+			path:  "redis.Resolver",
+			value: "x",
+			err:   `unknown configuration path "redis.Resolver"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c C
+
+			err := c.Set(tt.path, tt.value)
+			if cont := testErrCheck(t, "Set()", tt.err, err); !cont {
+				return
+			}
+
+			cmpDiff(t, "C", cmp.Diff(tt.want, c))
+		})
+	}
+}
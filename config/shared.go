@@ -0,0 +1,36 @@
+package config
+
+import "sync"
+
+var (
+	sharedOnce sync.Once
+	sharedC    C
+	sharedErr  error
+
+	// sharedLoad is how Shared loads the configuration, replaced in tests.
+	sharedLoad = LoadEnv
+)
+
+// Shared loads the configuration with LoadEnv the first time it's called, and
+// returns the same configuration, or error, every time after. It's for
+// subsystems of one process that each need the configuration, as LoadEnv
+// unsets the secrets it reads and so can't be called twice. The maps and
+// slices in the C are shared between callers, so they mustn't be modified.
+//
+// Use LoadEnv, or LoadEnvWith, instead when you need control over when and
+// how the configuration is loaded.
+func Shared() (C, error) {
+	sharedOnce.Do(func() {
+		sharedC, sharedErr = sharedLoad()
+	})
+
+	return sharedC, sharedErr
+}
+
+// ResetSharedForTest clears the configuration cached by Shared, so the next
+// call loads it again. It's only meant for tests, and isn't safe to call while
+// the package is in use by other goroutines.
+func ResetSharedForTest() {
+	sharedOnce = sync.Once{}
+	sharedC, sharedErr = C{}, nil
+}
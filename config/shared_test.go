@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestShared(t *testing.T) {
+	defer func(prev func() (C, error)) { sharedLoad = prev }(sharedLoad)
+	defer ResetSharedForTest()
+
+	var calls int
+
+	sharedLoad = func() (C, error) {
+		calls++
+		return C{Port: uint16(8080 + calls)}, nil
+	}
+
+	ResetSharedForTest()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if c, err := Shared(); err != nil || c.Port != 8081 {
+				t.Errorf("Shared() = %d, %v, want 8081, <nil>", c.Port, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("Shared() loaded the configuration %d times, want 1", calls)
+	}
+
+	ResetSharedForTest()
+
+	if c, _ := Shared(); c.Port != 8082 {
+		t.Fatalf("Shared() after ResetSharedForTest() Port = %d, want 8082", c.Port)
+	}
+
+	// errors are cached too
+	ResetSharedForTest()
+
+	sharedLoad = func() (C, error) {
+		calls++
+		return C{}, errors.New("broken")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := Shared(); err == nil || err.Error() != "broken" {
+			t.Fatalf("Shared() error = %v, want broken", err)
+		}
+	}
+
+	if calls != 3 {
+		t.Fatalf("Shared() loaded the configuration %d times, want 3", calls)
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// defaultShutdownTimeout is the ShutdownTimeout if one isn't set.
+	defaultShutdownTimeout = 25 * time.Second
+
+	// herokuShutdownGrace is how long Heroku waits after sending SIGTERM
+	// before killing a dyno.
+	herokuShutdownGrace = 30 * time.Second
+)
+
+// ShutdownContext returns a context derived from parent with the
+// ShutdownTimeout deadline, to bound graceful shutdown (e.g., of the HTTP
+// server, or with CloseRedis).
+func (c C) ShutdownContext(parent context.Context) (context.Context, context.CancelFunc) {
+	timeout := c.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	return context.WithTimeout(parent, timeout)
+}
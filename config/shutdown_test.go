@@ -0,0 +1,35 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestC_ShutdownContext(t *testing.T) {
+	tests := []struct {
+		name string
+		c    C
+		want time.Duration
+	}{
+		{name: "default", want: 25 * time.Second},
+		{name: "configured", c: C{ShutdownTimeout: 10 * time.Second}, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.c.ShutdownContext(context.Background())
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				t.Fatal("ShutdownContext() has no deadline")
+			}
+
+			// allow for the time between the context being made and now
+			if got := time.Until(deadline); got > tt.want || got < tt.want-time.Second {
+				t.Fatalf("deadline in %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gobridge/gopherbot/signing"
+)
+
+// truncatedMarker is appended to messages shortened by TruncateMessage.
+const truncatedMarker = "…"
+
+// slackMethodRE matches Slack API method names, which are a lowercase group
+// and one or more actions separated by dots (e.g., chat.postMessage or
+// admin.users.list).
+var slackMethodRE = regexp.MustCompile(`^[a-z]+(\.[a-zA-Z]+)+$`)
+
+var (
+	// channelIDRE matches Slack conversation IDs, which are uppercase letters
+	// and digits starting with C (public channels), G (private channels), or
+	// D (direct messages). Channel names can't contain uppercase letters, so
+	// the two can't be confused.
+	channelIDRE = regexp.MustCompile(`^[CGD][A-Z0-9]*[0-9][A-Z0-9]*$`)
+
+	// channelNameRE matches Slack channel names, which are at most 80
+	// lowercase letters, digits, hyphens, underscores, and periods.
+	channelNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,79}$`)
+)
+
+// defaultMaxMessageLength is the number of characters after which Slack
+// truncates messages.
+const defaultMaxMessageLength = 40000
+
+// slackMaxBlocks is the most blocks Slack accepts in a message.
+const slackMaxBlocks = 50
+
+const (
+	// TransportEvents is when events are received from Slack as Events API
+	// webhooks.
+	TransportEvents = "events"
+
+	// TransportSocket is when events are received from Slack over a Socket
+	// Mode connection.
+	TransportSocket = "socket"
+)
+
+const (
+	// AckModeImmediate is when events are acknowledged to Slack as soon as
+	// they're received, before they're processed.
+	AckModeImmediate = "immediate"
+
+	// AckModeAfter is when events are acknowledged to Slack once they've been
+	// processed, or AckTimeout has passed.
+	AckModeAfter = "after"
+
+	// slackAckDeadline is how long Slack waits for an event to be
+	// acknowledged before re-delivering it.
+	slackAckDeadline = 3 * time.Second
+
+	defaultAckTimeout = 2500 * time.Millisecond
+
+	// defaultMaxRetryNum is Slack's own number of event re-deliveries, so by
+	// default every retry is processed.
+	defaultMaxRetryNum = 3
+)
+
+const (
+	// SigningModeHMAC is when Slack requests are verified using the HMAC
+	// signing secret. This is the method Slack recommends.
+	SigningModeHMAC = "hmac"
+
+	// SigningModeToken is when Slack requests are verified using the legacy
+	// verification token, which Slack has deprecated.
+	SigningModeToken = "token"
+
+	// tokenSunsetLayout is the time.Parse layout of Slack.TokenSunset.
+	tokenSunsetLayout = "2006-01-02"
+)
+
+// SlackEnabled returns whether the bot should connect to Slack, which it
+// shouldn't if Slack.Disabled is set. Callers should skip creating Slack
+// clients and serving Slack requests when it's false.
+func (c C) SlackEnabled() bool {
+	return !c.Slack.Disabled
+}
+
+// SigningMode returns how requests from Slack should be verified. It returns
+// SigningModeHMAC if RequestSecret is set, SigningModeToken if only
+// RequestToken is set, and an empty string if neither is.
+func (s S) SigningMode() string {
+	switch {
+	case len(s.RequestSecret) > 0:
+		return SigningModeHMAC
+	case len(s.RequestToken) > 0:
+		return SigningModeToken
+	default:
+		return ""
+	}
+}
+
+// tokenSunset parses TokenSunset, returning the start of that day in UTC.
+func (s S) tokenSunset() (time.Time, error) {
+	return time.Parse(tokenSunsetLayout, s.TokenSunset)
+}
+
+// Transport returns how we receive events from Slack. If TransportMode isn't
+// set, it's TransportSocket when an AppLevelToken is configured, and
+// TransportEvents otherwise.
+func (s S) Transport() string {
+	switch {
+	case len(s.TransportMode) > 0:
+		return s.TransportMode
+	case len(s.AppLevelToken) > 0:
+		return TransportSocket
+	default:
+		return TransportEvents
+	}
+}
+
+// AckImmediately returns whether the event handler should acknowledge events
+// to Slack before processing them, rather than after.
+func (s S) AckImmediately() bool {
+	return s.AckMode != AckModeAfter
+}
+
+// IsEnterprise returns whether the app is installed on an Enterprise Grid
+// organization, meaning tokens need to be looked up by EnterpriseID in
+// addition to TeamID.
+func (s S) IsEnterprise() bool {
+	return len(s.EnterpriseID) > 0
+}
+
+// MethodAllowed returns whether the bot may call the Slack API method, per
+// AllowedAPIMethods. All methods are allowed if AllowedAPIMethods is empty.
+func (s S) MethodAllowed(method string) bool {
+	if len(s.AllowedAPIMethods) == 0 {
+		return true
+	}
+
+	for _, m := range s.AllowedAPIMethods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// VerifyRequest verifies that a request came from Slack, using the HMAC
+// signature in the X-Slack-Signature and X-Slack-Request-Timestamp headers
+// generated with RequestSecret. Requests with a timestamp older than
+// MaxTimestampSkew (or 5 minutes, if it's not set) are rejected to prevent
+// replay attacks.
+//
+// The error will be a *signing.TimestampError if the timestamp is too old, and
+// signing.ErrSignatureMismatch if the signature is wrong.
+func (s S) VerifyRequest(header http.Header, body []byte) error {
+	if len(s.RequestSecret) == 0 {
+		return errors.New("Slack.RequestSecret is not set")
+	}
+
+	window := s.MaxTimestampSkew
+	if window <= 0 {
+		window = signing.DefaultWindow
+	}
+
+	return signing.ValidateAt(s.RequestSecret, signing.Request{
+		Timestamp: header.Get(signing.SlackTimestampHeader),
+		Signature: header.Get(signing.SlackSignatureHeader),
+		Body:      body,
+	}, window, clock.Now())
+}
+
+// ShouldProcessRetry returns whether the Slack event request with header
+// should be processed, which is false for re-deliveries whose X-Slack-Retry-Num
+// exceeds MaxRetryNum. Requests that aren't retries, or whose retry number
+// can't be parsed, are always processed so that events aren't lost.
+func (s S) ShouldProcessRetry(header http.Header) bool {
+	rn := header.Get("X-Slack-Retry-Num")
+	if len(rn) == 0 {
+		return true
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(rn))
+	if err != nil {
+		return true
+	}
+
+	return n <= s.MaxRetryNum
+}
+
+// IsChannelID returns whether s is a Slack conversation ID (e.g., C0123ABCD),
+// rather than a channel name.
+func IsChannelID(s string) bool {
+	return channelIDRE.MatchString(s)
+}
+
+// NormalizeChannel returns the canonical form of the Slack channel reference
+// ref, which may be a channel ID (e.g., "C0123ABCD") or a channel name with or
+// without a leading '#' (e.g., "#general" or "general"). IDs are returned as
+// they are, while names are returned lowercased without the '#', as Slack
+// treats them case-insensitively.
+func NormalizeChannel(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+
+	if IsChannelID(ref) {
+		return ref, nil
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(ref, "#"))
+	if !channelNameRE.MatchString(name) {
+		return "", fmt.Errorf("invalid Slack channel %q, expected an ID or a name of up to 80 letters, digits, '-', '_', or '.'", ref)
+	}
+
+	return name, nil
+}
+
+// TruncateMessage shortens s to at most c.MaxMessageLength characters, so that
+// we control how long messages are cut off instead of Slack. Truncated
+// messages end with an ellipsis, and are only ever cut between runes.
+func (c C) TruncateMessage(s string) string {
+	if c.MaxMessageLength <= 0 || utf8.RuneCountInString(s) <= c.MaxMessageLength {
+		return s
+	}
+
+	keep := c.MaxMessageLength - utf8.RuneCountInString(truncatedMarker)
+
+	var n int
+
+	for i := range s {
+		if n == keep {
+			return s[:i] + truncatedMarker
+		}
+
+		n++
+	}
+
+	return s
+}
+
+// defaultCommandPrefix is the CommandPrefix if one isn't set.
+const defaultCommandPrefix = "!"
+
+// ParseCommand parses text as a command, returning the command name and the
+// rest of the text as its arguments. Text is a command if it starts with
+// CommandPrefix (e.g., "!help me"), or with RespondToMentions, if it starts
+// with a mention of BotUserID in Slack's <@U123> form (e.g., "<@U123> help
+// me"). A prefix is allowed after the mention, as is a colon, so
+// "<@U123>: !help" is the help command too. If BotUserID isn't set, no
+// mention is a command, as it may be of someone else. The bool is false if
+// text isn't a command.
+func (s S) ParseCommand(text string) (cmd string, args string, ok bool) {
+	text = strings.TrimSpace(text)
+
+	switch {
+	case s.RespondToMentions && strings.HasPrefix(text, "<@"):
+		end := strings.IndexByte(text, '>')
+		if end < 0 {
+			return "", "", false
+		}
+
+		// mentions may include a display name, as in <@U123|gopher>
+		id := text[2:end]
+		if i := strings.IndexByte(id, '|'); i >= 0 {
+			id = id[:i]
+		}
+
+		// without BotUserID we can't tell if the bot or someone else was mentioned
+		if len(s.BotUserID) == 0 || id != s.BotUserID {
+			return "", "", false
+		}
+
+		text = strings.TrimSpace(strings.TrimPrefix(text[end+1:], ":"))
+
+		if len(s.CommandPrefix) > 0 {
+			text = strings.TrimPrefix(text, s.CommandPrefix)
+		}
+
+	case len(s.CommandPrefix) > 0 && strings.HasPrefix(text, s.CommandPrefix):
+		text = text[len(s.CommandPrefix):]
+
+	default:
+		return "", "", false
+	}
+
+	// a prefix followed by a space (e.g., "! nice") isn't a command
+	if len(text) == 0 || unicode.IsSpace(rune(text[0])) {
+		return "", "", false
+	}
+
+	if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+		return text[:i], strings.TrimSpace(text[i:]), true
+	}
+
+	return text, "", true
+}
@@ -0,0 +1,412 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobridge/gopherbot/signing"
+)
+
+func TestC_SlackEnabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		enabled bool
+	}{
+		{name: "development_without_token", env: map[string]string{}, enabled: false},
+		{name: "development_with_token", env: map[string]string{"GOPHER_SLACK_BOT_ACCESS_TOKEN": "xoxb-123"}, enabled: true},
+		{name: "development_forced_on", env: map[string]string{"GOPHER_SLACK_DISABLED": "0"}, enabled: true},
+		{name: "staging_without_token", env: map[string]string{"ENV": "staging"}, enabled: true},
+		{name: "testing_disabled", env: map[string]string{"ENV": "testing", "GOPHER_SLACK_DISABLED": "1"}, enabled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := load(func(k string) string { return tt.env[k] })
+			testErrCheck(t, "load()", "", err)
+
+			if got := c.SlackEnabled(); got != tt.enabled {
+				t.Fatalf("SlackEnabled() = %t, want %t", got, tt.enabled)
+			}
+
+			if tt.enabled {
+				return
+			}
+
+			// nothing Slack needs is required once it's disabled
+			testErrCheck(t, "Validate()", "", c.Validate())
+		})
+	}
+}
+
+func TestS_SigningMode(t *testing.T) {
+	tests := []struct {
+		name string
+		s    S
+		want string
+	}{
+		{
+			name: "both",
+			s:    S{RequestSecret: "abc", RequestToken: "xyz"},
+			want: SigningModeHMAC,
+		},
+		{
+			name: "secret",
+			s:    S{RequestSecret: "abc"},
+			want: SigningModeHMAC,
+		},
+		{
+			name: "token",
+			s:    S{RequestToken: "xyz"},
+			want: SigningModeToken,
+		},
+		{
+			name: "neither",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.SigningMode(); got != tt.want {
+				t.Fatalf("SigningMode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestS_Transport(t *testing.T) {
+	tests := []struct {
+		name string
+		s    S
+		want string
+	}{
+		{
+			name: "inferred_events",
+			s:    S{RequestSecret: "abc"},
+			want: TransportEvents,
+		},
+		{
+			name: "inferred_socket",
+			s:    S{AppLevelToken: "xapp-123"},
+			want: TransportSocket,
+		},
+		{
+			name: "explicit",
+			s:    S{TransportMode: TransportEvents, RequestSecret: "abc", AppLevelToken: "xapp-123"},
+			want: TransportEvents,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Transport(); got != tt.want {
+				t.Fatalf("Transport() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestS_AckImmediately(t *testing.T) {
+	if !(S{AckMode: AckModeImmediate}).AckImmediately() {
+		t.Fatal("AckImmediately() = false for AckModeImmediate")
+	}
+
+	if (S{AckMode: AckModeAfter}).AckImmediately() {
+		t.Fatal("AckImmediately() = true for AckModeAfter")
+	}
+}
+
+func TestS_IsEnterprise(t *testing.T) {
+	if (S{TeamID: "T123"}).IsEnterprise() {
+		t.Fatal("IsEnterprise() = true without an EnterpriseID")
+	}
+
+	if !(S{TeamID: "T123", EnterpriseID: "E456"}).IsEnterprise() {
+		t.Fatal("IsEnterprise() = false with an EnterpriseID")
+	}
+}
+
+func TestS_MethodAllowed(t *testing.T) {
+	if !(S{}).MethodAllowed("admin.users.list") {
+		t.Fatal("MethodAllowed() = false without an allowlist")
+	}
+
+	s := S{AllowedAPIMethods: []string{"chat.postMessage", "users.info"}}
+
+	if !s.MethodAllowed("chat.postMessage") {
+		t.Fatal("MethodAllowed(chat.postMessage) = false, but it's in the allowlist")
+	}
+
+	if s.MethodAllowed("admin.users.list") {
+		t.Fatal("MethodAllowed(admin.users.list) = true, but it's not in the allowlist")
+	}
+}
+
+func TestS_ParseCommand(t *testing.T) {
+	s := S{CommandPrefix: "!", RespondToMentions: true, BotUserID: "U123"}
+
+	tests := []struct {
+		name string
+		s    S
+		text string
+		cmd  string
+		args string
+		ok   bool
+	}{
+		{name: "prefix", s: s, text: "!help", cmd: "help", ok: true},
+		{name: "prefix_args", s: s, text: "  !remind me  in 5m ", cmd: "remind", args: "me  in 5m", ok: true},
+		{name: "prefix_space", s: s, text: "! nice", ok: false},
+		{name: "prefix_only", s: s, text: "!", ok: false},
+		{name: "plain_text", s: s, text: "help", ok: false},
+		{name: "multi_char_prefix", s: S{CommandPrefix: "gopher:"}, text: "gopher:ping now", cmd: "ping", args: "now", ok: true},
+		{name: "mention", s: s, text: "<@U123> help me", cmd: "help", args: "me", ok: true},
+		{name: "mention_colon", s: s, text: "<@U123>: help", cmd: "help", ok: true},
+		{name: "mention_display_name", s: s, text: "<@U123|gopher> help", cmd: "help", ok: true},
+		{name: "mention_and_prefix", s: s, text: "<@U123> !help", cmd: "help", ok: true},
+		{name: "mention_only", s: s, text: "<@U123>", ok: false},
+		{name: "mention_other_user", s: s, text: "<@U999> help", ok: false},
+		{name: "mention_without_bot_user_id", s: S{RespondToMentions: true}, text: "<@U999> help", ok: false},
+		{name: "mention_without_bot_user_id_prefix", s: S{RespondToMentions: true, CommandPrefix: "!"}, text: "<@U999> !help", ok: false},
+		{name: "mention_unterminated", s: s, text: "<@U123 help", ok: false},
+		{name: "mentions_off", s: S{CommandPrefix: "!"}, text: "<@U123> help", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, args, ok := tt.s.ParseCommand(tt.text)
+			if cmd != tt.cmd || args != tt.args || ok != tt.ok {
+				t.Fatalf("ParseCommand(%q) = %q, %q, %t, want %q, %q, %t", tt.text, cmd, args, ok, tt.cmd, tt.args, tt.ok)
+			}
+		})
+	}
+}
+
+func TestS_ShouldProcessRetry(t *testing.T) {
+	tests := []struct {
+		name string
+		max  int
+		num  string
+		want bool
+	}{
+		{name: "not_retry", max: 0, want: true},
+		{name: "under_max", max: 3, num: "1", want: true},
+		{name: "at_max", max: 3, num: "3", want: true},
+		{name: "over_max", max: 3, num: "4", want: false},
+		{name: "ignore_all_retries", max: 0, num: "1", want: false},
+		{name: "unparseable", max: 0, num: "many", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			if len(tt.num) > 0 {
+				header.Set("X-Slack-Retry-Num", tt.num)
+				header.Set("X-Slack-Retry-Reason", "http_timeout")
+			}
+
+			if got := (S{MaxRetryNum: tt.max}).ShouldProcessRetry(header); got != tt.want {
+				t.Fatalf("ShouldProcessRetry() with X-Slack-Retry-Num %q = %t, want %t", tt.num, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeChannel(t *testing.T) {
+	tests := []struct {
+		name   string
+		ref    string
+		want   string
+		id     bool
+		errStr string
+	}{
+		{name: "hash_name", ref: "#general", want: "general"},
+		{name: "name", ref: "general", want: "general"},
+		{name: "mixed_case_name", ref: "#Go-Nuts", want: "go-nuts"},
+		{name: "name_punctuation", ref: "golang_jobs.eu", want: "golang_jobs.eu"},
+		{name: "spaces", ref: "  #general ", want: "general"},
+		{name: "channel_id", ref: "C0123", want: "C0123", id: true},
+		{name: "private_channel_id", ref: "G01ABCDEF9", want: "G01ABCDEF9", id: true},
+		{name: "dm_id", ref: "D024BE91L", want: "D024BE91L", id: true},
+		{name: "general_uppercase", ref: "GENERAL", want: "general"},
+		{name: "empty", ref: "", errStr: `invalid Slack channel ""`},
+		{name: "hash_only", ref: "#", errStr: `invalid Slack channel "#"`},
+		{name: "double_hash", ref: "##general", errStr: "invalid Slack channel"},
+		{name: "space_in_name", ref: "go nuts", errStr: "invalid Slack channel"},
+		{name: "leading_hyphen", ref: "-general", errStr: "invalid Slack channel"},
+		{name: "too_long", ref: strings.Repeat("a", 81), errStr: "invalid Slack channel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeChannel(tt.ref)
+			if cont := testErrCheck(t, "NormalizeChannel()", tt.errStr, err); !cont {
+				return
+			}
+
+			if got != tt.want {
+				t.Fatalf("NormalizeChannel(%q) = %q, want %q", tt.ref, got, tt.want)
+			}
+
+			if id := IsChannelID(got); id != tt.id {
+				t.Fatalf("IsChannelID(%q) = %t, want %t", got, id, tt.id)
+			}
+		})
+	}
+}
+
+func TestC_TruncateMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		max  int
+		in   string
+		want string
+	}{
+		{
+			name: "short",
+			max:  5,
+			in:   "abc",
+			want: "abc",
+		},
+		{
+			name: "at_limit",
+			max:  5,
+			in:   "abcde",
+			want: "abcde",
+		},
+		{
+			name: "over_limit",
+			max:  5,
+			in:   "abcdef",
+			want: "abcd…",
+		},
+		{
+			name: "multibyte",
+			max:  3,
+			in:   "ʕ◔ϖ◔ʔ",
+			want: "ʕ◔…",
+		},
+		{
+			name: "unset",
+			in:   "abcdef",
+			want: "abcdef",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (C{MaxMessageLength: tt.max}).TruncateMessage(tt.in); got != tt.want {
+				t.Fatalf("TruncateMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func hmacSignature(key, data string) string {
+	m := hmac.New(sha256.New, []byte(key))
+	_, _ = m.Write([]byte(data))
+
+	return fmt.Sprintf("v0=%x", m.Sum(nil))
+}
+
+func TestS_VerifyRequest(t *testing.T) {
+	const body = `{"type":"event_callback"}`
+
+	signed := func(t *testing.T, key string) http.Header {
+		t.Helper()
+
+		r, err := http.NewRequest(http.MethodPost, "http://example.org/slack/event", strings.NewReader(body))
+		testErrCheck(t, "http.NewRequest()", "", err)
+		testErrCheck(t, "signing.Sign()", "", signing.Sign(key, r))
+
+		return r.Header
+	}
+
+	s := S{RequestSecret: "abc123"}
+
+	t.Run("valid", func(t *testing.T) {
+		testErrCheck(t, "VerifyRequest()", "", s.VerifyRequest(signed(t, "abc123"), []byte(body)))
+	})
+
+	t.Run("bad_signature", func(t *testing.T) {
+		err := s.VerifyRequest(signed(t, "xyz890"), []byte(body))
+		if !errors.Is(err, signing.ErrSignatureMismatch) {
+			t.Fatalf("VerifyRequest() error = %v, want signing.ErrSignatureMismatch", err)
+		}
+	})
+
+	t.Run("stale_timestamp", func(t *testing.T) {
+		h := signed(t, "abc123")
+		h.Set(signing.SlackTimestampHeader, "1531420618")
+
+		var te *signing.TimestampError
+		if err := s.VerifyRequest(h, []byte(body)); !errors.As(err, &te) {
+			t.Fatalf("VerifyRequest() error = %v, want *signing.TimestampError", err)
+		}
+	})
+
+	t.Run("clock", func(t *testing.T) {
+		h := http.Header{}
+		h.Set(signing.SlackTimestampHeader, "1531420618")
+		h.Set(signing.SlackSignatureHeader, hmacSignature("abc123", "v0:1531420618:"+body))
+
+		clk := NewFakeClock(time.Unix(1531420618, 0).Add(time.Minute))
+		defer SetClockForTest(clk)()
+
+		testErrCheck(t, "VerifyRequest()", "", s.VerifyRequest(h, []byte(body)))
+
+		clk.Advance(signing.DefaultWindow)
+
+		var te *signing.TimestampError
+		if err := s.VerifyRequest(h, []byte(body)); !errors.As(err, &te) {
+			t.Fatalf("VerifyRequest() error = %v, want *signing.TimestampError", err)
+		}
+	})
+
+	t.Run("skew_boundary", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			skew  time.Duration
+			age   time.Duration
+			stale bool
+		}{
+			{name: "default_inside", age: 290 * time.Second},
+			{name: "default_outside", age: 310 * time.Second, stale: true},
+			{name: "custom_inside", skew: time.Minute, age: 50 * time.Second},
+			{name: "custom_outside", skew: time.Minute, age: 70 * time.Second, stale: true},
+			{name: "custom_extended", skew: 10 * time.Minute, age: 590 * time.Second},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ts := strconv.FormatInt(time.Now().Add(-tt.age).Unix(), 10)
+
+				h := http.Header{}
+				h.Set(signing.SlackTimestampHeader, ts)
+				h.Set(signing.SlackSignatureHeader, hmacSignature("abc123", "v0:"+ts+":"+body))
+
+				err := S{RequestSecret: "abc123", MaxTimestampSkew: tt.skew}.VerifyRequest(h, []byte(body))
+
+				var te *signing.TimestampError
+				if stale := errors.As(err, &te); stale != tt.stale {
+					t.Fatalf("VerifyRequest() error = %v, want stale = %t", err, tt.stale)
+				}
+
+				if !tt.stale {
+					testErrCheck(t, "VerifyRequest()", "", err)
+				}
+			})
+		}
+	})
+
+	t.Run("no_secret", func(t *testing.T) {
+		err := S{}.VerifyRequest(signed(t, "abc123"), []byte(body))
+		testErrCheck(t, "VerifyRequest()", "Slack.RequestSecret is not set", err)
+	})
+}
@@ -0,0 +1,34 @@
+package slackclient
+
+import (
+	"github.com/gobridge/gopherbot/config"
+	"github.com/slack-go/slack"
+)
+
+// SplitBlocks chunks blocks in to groups of at most c.MaxBlocksPerMessage,
+// in order, so each can be posted as its own message without Slack rejecting
+// it. If c.MaxBlocksPerMessage isn't positive, blocks is returned as a single
+// group. It returns nil if there are no blocks.
+//
+// It's a function here rather than a method on config.C, so that config
+// doesn't depend on the Slack SDK.
+func SplitBlocks(c config.C, blocks []slack.Block) [][]slack.Block {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	max := c.MaxBlocksPerMessage
+	if max <= 0 {
+		max = len(blocks)
+	}
+
+	groups := make([][]slack.Block, 0, (len(blocks)+max-1)/max)
+
+	for len(blocks) > max {
+		// capped, so appending to a group can't overwrite the next one
+		groups = append(groups, blocks[:max:max])
+		blocks = blocks[max:]
+	}
+
+	return append(groups, blocks)
+}
@@ -0,0 +1,73 @@
+package slackclient
+
+import (
+	"testing"
+
+	"github.com/gobridge/gopherbot/config"
+	"github.com/slack-go/slack"
+)
+
+func TestSplitBlocks(t *testing.T) {
+	blocks := func(n int) []slack.Block {
+		b := make([]slack.Block, n)
+		for i := range b {
+			b[i] = slack.NewDividerBlock()
+		}
+
+		return b
+	}
+
+	tests := []struct {
+		name  string
+		max   int
+		n     int
+		sizes []int
+	}{
+		{name: "none", max: 50, n: 0},
+		{name: "one", max: 50, n: 1, sizes: []int{1}},
+		{name: "under_max", max: 50, n: 49, sizes: []int{49}},
+		{name: "at_max", max: 50, n: 50, sizes: []int{50}},
+		{name: "over_max", max: 50, n: 51, sizes: []int{50, 1}},
+		{name: "twice_max", max: 50, n: 100, sizes: []int{50, 50}},
+		{name: "small_max", max: 3, n: 7, sizes: []int{3, 3, 1}},
+		{name: "max_of_one", max: 1, n: 3, sizes: []int{1, 1, 1}},
+		{name: "unset_max", max: 0, n: 60, sizes: []int{60}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := blocks(tt.n)
+
+			got := SplitBlocks(config.C{MaxBlocksPerMessage: tt.max}, in)
+
+			if len(got) != len(tt.sizes) {
+				t.Fatalf("SplitBlocks() returned %d groups, want %d", len(got), len(tt.sizes))
+			}
+
+			var i int
+
+			for g, group := range got {
+				if len(group) != tt.sizes[g] {
+					t.Fatalf("SplitBlocks() group %d has %d blocks, want %d", g, len(group), tt.sizes[g])
+				}
+
+				for _, b := range group {
+					if b != in[i] {
+						t.Fatalf("SplitBlocks() group %d is out of order", g)
+					}
+
+					i++
+				}
+			}
+		})
+	}
+
+	// appending to a group mustn't overwrite the next one
+	in := blocks(4)
+	got := SplitBlocks(config.C{MaxBlocksPerMessage: 2}, in)
+	_ = append(got[0], slack.NewDividerBlock())
+
+	if got[1][0] != in[2] {
+		t.Fatal("appending to a group overwrote the next one")
+	}
+}
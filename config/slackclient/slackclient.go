@@ -0,0 +1,74 @@
+// Package slackclient builds a slack-go client from the configuration. It's a
+// separate package so that only the binaries that call the Slack API depend
+// on the SDK, and not everything importing config.
+package slackclient
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gobridge/gopherbot/config"
+	"github.com/slack-go/slack"
+)
+
+// DefaultUserAgent is the User-Agent sent with Slack API calls if
+// S.UserAgent isn't set.
+const DefaultUserAgent = "gopherbot"
+
+// New returns a Slack client using the bot access token, the API URL and
+// User-Agent from c.Slack, and c.HTTPClient. If c.HTTPClient is nil, a client
+// using http.DefaultTransport is used, which respects the HTTP_PROXY and
+// HTTPS_PROXY environment variables.
+func New(c config.C) (*slack.Client, error) {
+	if len(c.Slack.BotAccessToken) == 0 {
+		return nil, errors.New("Slack.BotAccessToken is required to create a Slack client")
+	}
+
+	hc := &http.Client{Timeout: 30 * time.Second}
+	if c.HTTPClient != nil {
+		// copied, so the User-Agent doesn't leak in to other uses of it
+		cp := *c.HTTPClient
+		hc = &cp
+	}
+
+	ua := c.Slack.UserAgent
+	if len(ua) == 0 {
+		ua = DefaultUserAgent
+	}
+
+	hc.Transport = &userAgentTransport{base: hc.Transport, userAgent: ua}
+
+	opts := []slack.Option{slack.OptionHTTPClient(hc)}
+
+	if u := c.Slack.APIURL; len(u) > 0 {
+		// the client appends method names directly to the URL
+		if !strings.HasSuffix(u, "/") {
+			u += "/"
+		}
+
+		opts = append(opts, slack.OptionAPIURL(u))
+	}
+
+	return slack.New(c.Slack.BotAccessToken, opts...), nil
+}
+
+// userAgentTransport is an http.RoundTripper that sets the User-Agent.
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	// RoundTrippers mustn't modify the request
+	r = r.Clone(r.Context())
+	r.Header.Set("User-Agent", t.userAgent)
+
+	return base.RoundTrip(r)
+}
@@ -0,0 +1,64 @@
+package slackclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gobridge/gopherbot/config"
+)
+
+func TestNew(t *testing.T) {
+	if _, err := New(config.C{}); err == nil {
+		t.Fatal("New() without a bot token expected an error")
+	}
+
+	var path, ua, auth string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, ua = r.URL.Path, r.Header.Get("User-Agent")
+
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse form: %v", err)
+		}
+
+		auth = r.PostForm.Get("token")
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "user_id": "U123"}`))
+	}))
+	defer srv.Close()
+
+	hc := srv.Client()
+
+	c := config.C{
+		HTTPClient: hc,
+		Slack: config.S{
+			BotAccessToken: "xoxb-123",
+			APIURL:         srv.URL + "/api",
+			UserAgent:      "gopherbot-test",
+		},
+	}
+
+	sc, err := New(c)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	r, err := sc.AuthTest()
+	if err != nil {
+		t.Fatalf("AuthTest() error = %v", err)
+	}
+
+	if r.UserID != "U123" {
+		t.Fatalf("UserID = %q, want U123", r.UserID)
+	}
+
+	if path != "/api/auth.test" || ua != "gopherbot-test" || auth != "xoxb-123" {
+		t.Fatalf("request = path %q, User-Agent %q, token %q", path, ua, auth)
+	}
+
+	if _, ok := hc.Transport.(*userAgentTransport); ok {
+		t.Fatal("New() modified the configured HTTPClient")
+	}
+}
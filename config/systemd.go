@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadFromSystemdCredentials loads the configuration like LoadEnv, and then
+// sets values from the credential files systemd provides in the directory
+// named by CREDENTIALS_DIRECTORY (see LoadCredential= in systemd.exec(5)).
+// Each filename is the path Set would take (e.g., "slack.bot_access_token" or
+// "redis.password") and its contents are the value, with trailing newlines
+// trimmed. Credentials take precedence over the environment.
+//
+// If CREDENTIALS_DIRECTORY isn't set, this is the same as LoadEnv.
+func LoadFromSystemdCredentials() (C, error) {
+	c, err := load(os.Getenv)
+	if err != nil {
+		return C{}, err
+	}
+
+	for _, k := range secretEnvKeys {
+		_ = os.Unsetenv(k) // paranoia
+	}
+
+	if dir := os.Getenv("CREDENTIALS_DIRECTORY"); len(dir) > 0 {
+		set, err := loadSystemdCredentials(&c, dir)
+		if err != nil {
+			return C{}, err
+		}
+
+		// the default depends on the bot token, which may be a credential
+		if !set["slack.disabled"] && len(os.Getenv("GOPHER_SLACK_DISABLED")) == 0 {
+			c.Slack.Disabled = defaultSlackDisabled(c)
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return C{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return c, nil
+}
+
+// loadSystemdCredentials sets the credentials in dir on c, returning the
+// paths that were set.
+func loadSystemdCredentials(c *C, dir string) (map[string]bool, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read systemd credentials directory %s: %w", dir, err)
+	}
+
+	set := make(map[string]bool, len(files))
+
+	for _, f := range files {
+		name := f.Name()
+
+		if strings.HasPrefix(name, ".") || f.IsDir() {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join(dir, name)) // #nosec G304 -- dir is provided by systemd
+		if err != nil {
+			return nil, fmt.Errorf("failed to read systemd credential %s: %w", name, err)
+		}
+
+		if err := c.Set(name, strings.TrimRight(string(b), "\r\n")); err != nil {
+			return nil, fmt.Errorf("failed to load systemd credential %s: %w", name, err)
+		}
+
+		set[name] = true
+	}
+
+	return set, nil
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFromSystemdCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopher-credentials")
+	testErrCheck(t, "ioutil.TempDir()", "", err)
+
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	creds := map[string]string{
+		"slack.bot_access_token": "xoxb-123\n",
+		"redis.password":         "hunter2\r\n",
+		".hidden":                "skipped",
+	}
+
+	for name, v := range creds {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(v), 0600)
+		testErrCheck(t, "ioutil.WriteFile()", "", err)
+	}
+
+	env := map[string]string{
+		"ENV":                           "staging",
+		"GOPHER_SLACK_TEAM_ID":          "T123",
+		"GOPHER_SLACK_BOT_ACCESS_TOKEN": "from-env",
+	}
+
+	set := func() {
+		for k, v := range env {
+			_ = os.Setenv(k, v)
+		}
+	}
+
+	defer func() {
+		for k := range env {
+			_ = os.Unsetenv(k)
+		}
+
+		_ = os.Unsetenv("CREDENTIALS_DIRECTORY")
+	}()
+
+	t.Run("credentials", func(t *testing.T) {
+		set()
+		_ = os.Setenv("CREDENTIALS_DIRECTORY", dir)
+
+		c, err := LoadFromSystemdCredentials()
+		testErrCheck(t, "LoadFromSystemdCredentials()", "", err)
+
+		if c.Slack.BotAccessToken != "xoxb-123" || c.Redis.Password != "hunter2" {
+			t.Fatalf("credentials not applied: BotAccessToken = %q, Redis.Password = %q", c.Slack.BotAccessToken, c.Redis.Password)
+		}
+
+		if c.Env != Staging || c.Slack.TeamID != "T123" {
+			t.Fatalf("Env/TeamID = %q/%q, want values from the environment", c.Env, c.Slack.TeamID)
+		}
+
+		if _, ok := os.LookupEnv("GOPHER_SLACK_BOT_ACCESS_TOKEN"); ok {
+			t.Error("GOPHER_SLACK_BOT_ACCESS_TOKEN is still set")
+		}
+	})
+
+	t.Run("development_bot_token_credential", func(t *testing.T) {
+		_ = os.Unsetenv("ENV")
+		_ = os.Unsetenv("GOPHER_SLACK_BOT_ACCESS_TOKEN")
+		_ = os.Setenv("CREDENTIALS_DIRECTORY", dir)
+
+		c, err := LoadFromSystemdCredentials()
+		testErrCheck(t, "LoadFromSystemdCredentials()", "", err)
+
+		if c.Env != Development || c.Slack.Disabled {
+			t.Fatalf("Env/Slack.Disabled = %s/%t, want development/false as the bot token is a credential", c.Env, c.Slack.Disabled)
+		}
+
+		// unless it's explicitly disabled
+		_ = os.Setenv("GOPHER_SLACK_DISABLED", "1")
+		defer func() { _ = os.Unsetenv("GOPHER_SLACK_DISABLED") }()
+
+		c, err = LoadFromSystemdCredentials()
+		testErrCheck(t, "LoadFromSystemdCredentials()", "", err)
+
+		if !c.Slack.Disabled {
+			t.Fatal("Slack.Disabled = false, want GOPHER_SLACK_DISABLED's true")
+		}
+	})
+
+	t.Run("no_directory", func(t *testing.T) {
+		set()
+		_ = os.Unsetenv("CREDENTIALS_DIRECTORY")
+
+		c, err := LoadFromSystemdCredentials()
+		testErrCheck(t, "LoadFromSystemdCredentials()", "", err)
+
+		if c.Slack.BotAccessToken != "from-env" {
+			t.Fatalf("BotAccessToken = %q, want %q", c.Slack.BotAccessToken, "from-env")
+		}
+	})
+
+	t.Run("unreadable_directory", func(t *testing.T) {
+		set()
+		_ = os.Setenv("CREDENTIALS_DIRECTORY", filepath.Join(dir, "missing"))
+
+		_, err := LoadFromSystemdCredentials()
+		testErrCheck(t, "LoadFromSystemdCredentials()", "failed to read systemd credentials directory", err)
+	})
+
+	t.Run("unknown_credential", func(t *testing.T) {
+		set()
+
+		bad, err := ioutil.TempDir("", "gopher-credentials")
+		testErrCheck(t, "ioutil.TempDir()", "", err)
+
+		defer func() { _ = os.RemoveAll(bad) }()
+
+		err = ioutil.WriteFile(filepath.Join(bad, "slack.bot_token"), []byte("x"), 0600)
+		testErrCheck(t, "ioutil.WriteFile()", "", err)
+
+		_ = os.Setenv("CREDENTIALS_DIRECTORY", bad)
+
+		_, err = LoadFromSystemdCredentials()
+		testErrCheck(t, "LoadFromSystemdCredentials()", `failed to load systemd credential slack.bot_token: unknown configuration path "slack.bot_token"`, err)
+	})
+}
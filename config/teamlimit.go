@@ -0,0 +1,87 @@
+package config
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// maxTeamLimiters is how many teams the limiter cache tracks before it
+	// starts evicting those that have been idle.
+	maxTeamLimiters = 1024
+
+	// teamLimiterIdle is how long a team's limiter goes unused before it may
+	// be evicted. A limiter idle this long has refilled, so evicting it and
+	// later creating a new one doesn't change how the team is limited.
+	teamLimiterIdle = 10 * time.Minute
+)
+
+// teamLimiterCache holds a rate.Limiter for each team, shared by every copy of
+// the configuration so that TeamLimiter returns the same limiter each time.
+type teamLimiterCache struct {
+	mu    sync.Mutex
+	teams map[string]*teamLimiter
+}
+
+type teamLimiter struct {
+	l    *rate.Limiter
+	last time.Time
+}
+
+var teamLimiters = &teamLimiterCache{
+	teams: make(map[string]*teamLimiter),
+}
+
+// get returns the limiter for teamID, creating it if needed. If perMinute has
+// changed since the limiter was created, it's updated to match.
+func (tc *teamLimiterCache) get(teamID string, perMinute int) *rate.Limiter {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	now := clock.Now()
+	limit := rate.Every(time.Minute / time.Duration(perMinute))
+
+	t, ok := tc.teams[teamID]
+	if !ok {
+		if len(tc.teams) >= maxTeamLimiters {
+			tc.prune(now)
+		}
+
+		t = &teamLimiter{l: rate.NewLimiter(limit, perMinute)}
+		tc.teams[teamID] = t
+	}
+
+	if t.l.Limit() != limit || t.l.Burst() != perMinute {
+		t.l.SetLimitAt(now, limit)
+		t.l.SetBurstAt(now, perMinute)
+	}
+
+	t.last = now
+
+	return t.l
+}
+
+// prune deletes the limiters that have been idle for teamLimiterIdle. tc.mu
+// must be held.
+func (tc *teamLimiterCache) prune(now time.Time) {
+	for id, t := range tc.teams {
+		if now.Sub(t.last) >= teamLimiterIdle {
+			delete(tc.teams, id)
+		}
+	}
+}
+
+// TeamLimiter returns the rate limiter for Slack API calls made on behalf of
+// the team teamID, allowing Slack.TeamRateLimit calls per minute with bursts of
+// up to a minute's worth. The limiter is cached, so each call for the same
+// team returns the same one, and limiters for teams that go idle are evicted
+// to bound memory. If TeamRateLimit is zero, the limiter allows everything.
+func (c C) TeamLimiter(teamID string) *rate.Limiter {
+	if c.Slack.TeamRateLimit <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+
+	return teamLimiters.get(teamID, c.Slack.TeamRateLimit)
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestC_TeamLimiter(t *testing.T) {
+	clk := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer SetClockForTest(clk)()
+
+	now := clk.Now()
+
+	orig := teamLimiters
+	teamLimiters = &teamLimiterCache{teams: make(map[string]*teamLimiter)}
+
+	defer func() { teamLimiters = orig }()
+
+	c := C{Slack: S{TeamRateLimit: 2}}
+
+	l := c.TeamLimiter("T1")
+	if l != c.TeamLimiter("T1") {
+		t.Fatal("TeamLimiter() returned a different limiter for the same team")
+	}
+
+	if !l.AllowN(now, 1) || !l.AllowN(now, 1) || l.AllowN(now, 1) {
+		t.Fatal("limiter should allow a burst of 2, and then limit")
+	}
+
+	// one team being limited mustn't affect another
+	if !c.TeamLimiter("T2").AllowN(now, 1) {
+		t.Fatal("T2 was limited by T1's calls")
+	}
+
+	// changing the limit updates the cached limiter
+	c.Slack.TeamRateLimit = 60
+	if l := c.TeamLimiter("T1"); l.Burst() != 60 {
+		t.Fatalf("Burst() = %d after changing TeamRateLimit, want 60", l.Burst())
+	}
+
+	if l := (C{}).TeamLimiter("T1"); !l.Allow() || l == c.TeamLimiter("T1") {
+		t.Fatal("TeamLimiter() without a TeamRateLimit should be unlimited")
+	}
+
+	// once the cache is full, idle teams are evicted
+	clk.Advance(teamLimiterIdle)
+
+	for i := 0; i < maxTeamLimiters; i++ {
+		c.TeamLimiter("B" + strconv.Itoa(i))
+	}
+
+	if _, ok := teamLimiters.teams["T1"]; ok {
+		t.Fatal("idle team T1 wasn't evicted")
+	}
+
+	if n := len(teamLimiters.teams); n != maxTeamLimiters {
+		t.Fatalf("cache has %d teams, want %d", n, maxTeamLimiters)
+	}
+}
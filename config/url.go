@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// loadURLTimeout bounds how long LoadURL may take to fetch the
+	// configuration.
+	loadURLTimeout = 10 * time.Second
+
+	// maxURLConfigBytes caps the size of the configuration LoadURL reads.
+	maxURLConfigBytes = 1 << 20
+)
+
+// LoadURL loads the configuration from JSON served at rawurl, such as by a
+// control plane, using the json tags on C as the keys. The request is sent
+// with token as a bearer token, and the response must have a JSON content type.
+// Fields missing from the JSON keep the defaults LoadEnv would use with only
+// ENV set to the JSON's "env". The fetch gives up after 10 seconds.
+//
+// The URL, token, and response body are left out of any errors, as they may
+// include secrets.
+func LoadURL(ctx context.Context, rawurl, token string) (C, error) {
+	ctx, cancel := context.WithTimeout(ctx, loadURLTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, rawurl, nil)
+	if err != nil {
+		return C{}, errors.New("failed to build config request: invalid URL")
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	hc := &http.Client{Timeout: loadURLTimeout}
+
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		// unwrap the *url.Error, as its message includes the URL
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err
+		}
+
+		return C{}, fmt.Errorf("failed to fetch config: %w", err)
+	}
+
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return C{}, fmt.Errorf("failed to fetch config: unexpected HTTP status %d", resp.StatusCode)
+	}
+
+	if mt, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err != nil || mt != "application/json" {
+		return C{}, fmt.Errorf("failed to fetch config: unexpected content type %q, want application/json", resp.Header.Get("Content-Type"))
+	}
+
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxURLConfigBytes+1))
+	if err != nil {
+		return C{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if len(b) > maxURLConfigBytes {
+		return C{}, fmt.Errorf("failed to read config: larger than %d bytes", maxURLConfigBytes)
+	}
+
+	c, err := load(func(string) string { return "" })
+	if err != nil {
+		return C{}, err
+	}
+
+	if err := json.Unmarshal(b, &c); err != nil {
+		return C{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	applyEnvDefaults(&c, jsonSetPaths(b))
+
+	if err := c.Validate(); err != nil {
+		return C{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoadURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("bad token token123"))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/config":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = w.Write([]byte(`{"env": "staging", "port": 1234, "slack": {"team_id": "T123"}}`))
+
+		case "/production":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"env": "production", "slack": {"request_secret": "abc"}}`))
+
+		case "/capitalized":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"env": "Production", "slack": {"request_secret": "abc"}}`))
+
+		case "/unknown_env":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"env": "prod"}`))
+
+		case "/html":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html></html>`))
+
+		case "/invalid":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"port": "abc"}`))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := LoadURL(context.Background(), srv.URL+"/config", "token123")
+	testErrCheck(t, "LoadURL()", "", err)
+
+	if c.Env != Staging || c.Port != 1234 || c.Slack.TeamID != "T123" {
+		t.Fatalf("LoadURL() = %+v, want the served configuration", c)
+	}
+
+	// defaults are kept for fields missing from the JSON
+	if c.HealthPath != "/healthz" || c.Slack.AckMode != AckModeImmediate {
+		t.Fatalf("HealthPath/AckMode = %q/%q, want the defaults", c.HealthPath, c.Slack.AckMode)
+	}
+
+	// as are the defaults for the served environment
+	if c.Redis.DB != 1 {
+		t.Fatalf("Redis.DB = %d, want staging's 1", c.Redis.DB)
+	}
+
+	c, err = LoadURL(context.Background(), srv.URL+"/production", "token123")
+	testErrCheck(t, "LoadURL()", "", err)
+
+	if c.Env != Production || c.Redis.DB != 0 || !c.MetricsEnabled {
+		t.Fatalf("LoadURL() Env/Redis.DB/MetricsEnabled = %s/%d/%t, want production/0/true", c.Env, c.Redis.DB, c.MetricsEnabled)
+	}
+
+	// the environment is matched ignoring case, like ENV
+	c, err = LoadURL(context.Background(), srv.URL+"/capitalized", "token123")
+	testErrCheck(t, "LoadURL()", "", err)
+
+	if c.Env != Production || c.Redis.DB != 0 || !c.MetricsEnabled {
+		t.Fatalf("LoadURL() Env/Redis.DB/MetricsEnabled = %s/%d/%t, want production/0/true", c.Env, c.Redis.DB, c.MetricsEnabled)
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		err   string
+	}{
+		{name: "unauthorized", path: "/config", token: "wrong", err: "failed to fetch config: unexpected HTTP status 401"},
+		{name: "content_type", path: "/html", token: "token123", err: `unexpected content type "text/html", want application/json`},
+		{name: "unknown_env", path: "/unknown_env", token: "token123", err: `failed to parse config: unknown environment "prod"`},
+		{name: "invalid", path: "/invalid", token: "token123", err: "failed to parse config: json: cannot unmarshal string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadURL(context.Background(), srv.URL+tt.path+"?secret=hunter2", tt.token)
+			testErrCheck(t, "LoadURL()", tt.err, err)
+		})
+	}
+
+	// secrets in the URL and token mustn't leak in to errors
+	_, err = LoadURL(context.Background(), "http://127.0.0.1:1/config?secret=hunter2", "token123")
+	testErrCheck(t, "LoadURL()", "failed to fetch config", err)
+
+	if s := err.Error(); strings.Contains(s, "hunter2") || strings.Contains(s, "token123") {
+		t.Fatalf("LoadURL() error includes secrets: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v2"
+)
+
+// yamlValue converts the struct v to an ordered YAML mapping, using the same
+// field names as the JSON encoding. Values implementing fmt.Stringer (like
+// durations and log levels) are rendered as strings, to keep them readable.
+func yamlValue(v reflect.Value) yaml.MapSlice {
+	t := v.Type()
+	m := make(yaml.MapSlice, 0, v.NumField())
+
+	for i := 0; i < v.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+
+		if isIgnored(f) {
+			continue
+		}
+
+		item := yaml.MapItem{Key: fieldName(f)}
+
+		if s, ok := fv.Interface().(fmt.Stringer); ok {
+			item.Value = s.String()
+		} else if fv.Kind() == reflect.Struct {
+			item.Value = yamlValue(fv)
+		} else {
+			item.Value = fv.Interface()
+		}
+
+		m = append(m, item)
+	}
+
+	return m
+}
+
+// MarshalYAML satisfies yaml.Marshaler, so that the configuration is
+// rendered like MarshalYAMLRedacted does, with log levels and durations by
+// name, but without redacting its secrets.
+func (c C) MarshalYAML() (interface{}, error) {
+	return yamlValue(reflect.ValueOf(c)), nil
+}
+
+// MarshalYAMLRedacted renders the full configuration as YAML, for debugging.
+// Like Redacted, the values of secrets are replaced with "****".
+func (c C) MarshalYAMLRedacted() ([]byte, error) {
+	return yaml.Marshal(yamlValue(reflect.ValueOf(c.Redacted())))
+}
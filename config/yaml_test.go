@@ -0,0 +1,98 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v2"
+)
+
+func TestC_MarshalYAMLRedacted(t *testing.T) {
+	c := C{
+		Env:  Production,
+		Port: 1234,
+		Redis: R{
+			Addr:     "redis.example.org:6379",
+			Password: "hunter2",
+		},
+		Slack: S{
+			TeamID:           "T123",
+			BotAccessToken:   "xoxb-123",
+			ClientSecret:     "client-secret",
+			RequestSecret:    "request-secret",
+			RequestToken:     "request-token",
+			MaxTimestampSkew: 5 * time.Minute,
+		},
+		DebugToken: "debug-token",
+	}
+
+	b, err := c.MarshalYAMLRedacted()
+	testErrCheck(t, "MarshalYAMLRedacted()", "", err)
+
+	out := string(b)
+
+	for _, secret := range []string{"hunter2", "xoxb-123", "client-secret", "request-secret", "request-token", "debug-token"} {
+		if strings.Contains(out, secret) {
+			t.Fatalf("MarshalYAMLRedacted() output contains secret %q:\n%s", secret, out)
+		}
+	}
+
+	var got struct {
+		Env   string `yaml:"env"`
+		Port  uint16 `yaml:"port"`
+		Redis struct {
+			Addr     string `yaml:"addr"`
+			Password string `yaml:"password"`
+		} `yaml:"redis"`
+		Slack struct {
+			TeamID           string `yaml:"team_id"`
+			BotAccessToken   string `yaml:"bot_access_token"`
+			MaxTimestampSkew string `yaml:"max_timestamp_skew"`
+		} `yaml:"slack"`
+	}
+
+	if err := yaml.Unmarshal(b, &got); err != nil {
+		t.Fatalf("failed to unmarshal YAML: %v\n%s", err, out)
+	}
+
+	if got.Env != "production" || got.Port != 1234 || got.Redis.Addr != "redis.example.org:6379" || got.Slack.TeamID != "T123" {
+		t.Fatalf("MarshalYAMLRedacted() = %+v, missing values:\n%s", got, out)
+	}
+
+	if got.Redis.Password != redactedValue || got.Slack.BotAccessToken != redactedValue {
+		t.Fatalf("secrets = %q, %q, want %q", got.Redis.Password, got.Slack.BotAccessToken, redactedValue)
+	}
+
+	if got.Slack.MaxTimestampSkew != "5m0s" {
+		t.Fatalf("Slack.MaxTimestampSkew = %q, want 5m0s", got.Slack.MaxTimestampSkew)
+	}
+}
+
+func TestC_MarshalYAML(t *testing.T) {
+	b, err := yaml.Marshal(C{LogLevel: zerolog.WarnLevel, Slack: S{MaxTimestampSkew: time.Minute}})
+	testErrCheck(t, "yaml.Marshal()", "", err)
+
+	var m struct {
+		LogLevel string `yaml:"log_level"`
+		Slack    struct {
+			MaxTimestampSkew string `yaml:"max_timestamp_skew"`
+		} `yaml:"slack"`
+	}
+
+	err = yaml.Unmarshal(b, &m)
+	testErrCheck(t, "yaml.Unmarshal()", "", err)
+
+	if m.LogLevel != "warn" || m.Slack.MaxTimestampSkew != "1m0s" {
+		t.Fatalf("yaml.Marshal() = %s, want log_level warn and max_timestamp_skew 1m0s", b)
+	}
+
+	var c C
+	err = c.Set("log_level", m.LogLevel)
+	testErrCheck(t, "Set()", "", err)
+
+	if c.LogLevel != zerolog.WarnLevel {
+		t.Fatalf("LogLevel = %s after setting it from YAML, want warn", c.LogLevel)
+	}
+}
@@ -14,7 +14,10 @@ require (
 	github.com/rs/zerolog v1.18.0
 	github.com/slack-go/slack v0.6.4
 	github.com/valyala/fastjson v1.5.1
+	golang.org/x/net v0.0.0-20200421231249-e086a090c8fd
+	golang.org/x/text v0.3.2
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
 	golang.org/x/tools v0.0.0-20200420001825-978e26b7c37c // indirect
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
-	gopkg.in/yaml.v2 v2.2.4 // indirect
+	gopkg.in/yaml.v2 v2.2.4
 )
@@ -23,6 +23,21 @@ const (
 	SlackSignatureHeader = "X-Slack-Signature"
 )
 
+// ErrSignatureMismatch is returned by Validate when the request's signature
+// doesn't match the one we generate.
+var ErrSignatureMismatch = errors.New("signature does not match")
+
+// TimestampError is returned by Validate when the request's timestamp is
+// outside of the allowed window, which protects against replay attacks.
+type TimestampError struct {
+	// Timestamp is the request timestamp, in seconds since the Unix epoch
+	Timestamp int64
+}
+
+func (e *TimestampError) Error() string {
+	return fmt.Sprintf("request timestamp (%d) too old", e.Timestamp)
+}
+
 // Request represents the pieces of a request needed to do a signature
 // validation. We take this instead of an *http.Request so that we don't need to
 // be responsible for rewinding its response body.
@@ -38,14 +53,18 @@ type Request struct {
 	Body []byte
 }
 
-func parseTimestamp(t string) (int64, error) {
+// DefaultWindow is how old a request's timestamp may be before Validate
+// rejects it, as recommended by Slack.
+const DefaultWindow = 5 * time.Minute
+
+func parseTimestamp(t string, window time.Duration, now time.Time) (int64, error) {
 	ts, err := strconv.ParseInt(t, 10, 64)
 	if err != nil {
 		return -1, fmt.Errorf("failed to parse %s header: %w", SlackTimestampHeader, err)
 	}
 
-	if time.Now().Unix()-ts > 300 { // was this more than 5 minutes ago?
-		return -1, fmt.Errorf("request timestamp (%d) too old", ts)
+	if now.Sub(time.Unix(ts, 0)) > window { // was this too long ago?
+		return -1, &TimestampError{Timestamp: ts}
 	}
 
 	return ts, nil
@@ -67,6 +86,20 @@ func genHMAC(key, data string) string {
 // Returned errors are meant to be logged, not to be sent back to the entity
 // making the request.
 func Validate(key string, r Request) error {
+	return ValidateWindow(key, r, DefaultWindow)
+}
+
+// ValidateWindow is like Validate, but it allows the request timestamp to be
+// at most window old instead of DefaultWindow. This is useful for tolerating
+// clock skew, at the cost of weakening replay protection.
+func ValidateWindow(key string, r Request, window time.Duration) error {
+	return ValidateAt(key, r, window, time.Now())
+}
+
+// ValidateAt is like ValidateWindow, but it checks the request timestamp
+// against now instead of the current time. This lets callers with their own
+// clock, such as the config package's, control the check.
+func ValidateAt(key string, r Request, window time.Duration, now time.Time) error {
 	if len(r.Timestamp) == 0 {
 		return fmt.Errorf("%s header not present", SlackTimestampHeader)
 	}
@@ -75,7 +108,7 @@ func Validate(key string, r Request) error {
 		return fmt.Errorf("%s header not present", SlackSignatureHeader)
 	}
 
-	ts, err := parseTimestamp(r.Timestamp)
+	ts, err := parseTimestamp(r.Timestamp, window, now)
 	if err != nil {
 		return err
 	}
@@ -86,7 +119,7 @@ func Validate(key string, r Request) error {
 		return nil
 	}
 
-	return errors.New("signature does not match")
+	return ErrSignatureMismatch
 }
 
 // Sign takes the signature key, and a request, and then signs the request using
@@ -132,6 +132,43 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidate_errorTypes(t *testing.T) {
+	err := Validate(slackExampleSecret, Request{
+		Timestamp: "1531420618",
+		Signature: "v0=55f41ec73231010289b54e669149ea021fccab11b5524355523533ce930cb739",
+	})
+
+	var te *TimestampError
+	if !errors.As(err, &te) {
+		t.Fatalf("Validate() error = %v, want *TimestampError", err)
+	}
+
+	if te.Timestamp != 1531420618 {
+		t.Fatalf("TimestampError.Timestamp = %d, want 1531420618", te.Timestamp)
+	}
+
+	err = Validate(slackExampleSecret, Request{
+		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		Signature: "v0=55f41ec73231010289b54e669149ea021fccab11b5524355523533ce930cb739",
+	})
+
+	if !errors.Is(err, ErrSignatureMismatch) {
+		t.Fatalf("Validate() error = %v, want ErrSignatureMismatch", err)
+	}
+}
+
+func TestValidateAt(t *testing.T) {
+	r := Request{
+		Timestamp: "1531420618",
+		Signature: tgenHMAC(t, "v0:1531420618:"),
+	}
+
+	sent := time.Unix(1531420618, 0)
+
+	testErrCheck(t, "ValidateAt()", "", ValidateAt(slackExampleSecret, r, DefaultWindow, sent.Add(DefaultWindow)))
+	testErrCheck(t, "ValidateAt()", "request timestamp (1531420618) too old", ValidateAt(slackExampleSecret, r, DefaultWindow, sent.Add(DefaultWindow+time.Second)))
+}
+
 type garbageRC struct{}
 
 func (garbageRC) Read(_ []byte) (int, error) {